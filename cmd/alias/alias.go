@@ -0,0 +1,175 @@
+package alias
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/hyphaene/hexa/cmd"
+	internalAlias "github.com/hyphaene/hexa/internal/alias"
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// GroupID groups the alias commands in hexa --help
+const GroupID = "aliases"
+
+// depthEnv counts nested alias runs, to stop an alias calling itself
+const depthEnv = "HEXA_ALIAS_DEPTH"
+
+const maxDepth = 8
+
+var (
+	// registered are the aliases added as commands, shadowed the ones hiding a hexa command
+	registered []internalAlias.Alias
+	shadowed   []internalAlias.Alias
+	// loadErr reports the invalid aliases, shown by hexa alias list
+	loadErr error
+)
+
+func init() {
+	cmd.RootCmd.AddCommand(AliasCmd)
+}
+
+var AliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Inspect the command aliases defined in config",
+	Long: `Aliases defined in the aliases section of the configuration are hexa commands:
+
+  aliases:
+    mine: jira sprint fetch --filter me
+    daily: ["jira sprint pulse", "jira sprint fetch in-progress --filter me"]
+    wip: jira ticket move {ticket} --status in-progress && jira ticket comment {ticket} 'On it'
+
+{placeholders} are filled with the positional arguments (hexa wip PROJ-42), in order of
+first appearance; other arguments are appended to the last step. Steps (a list, or
+commands joined with &&) run one after the other and stop at the first failure.`,
+}
+
+// Register adds the configured aliases as hexa subcommands. It must run once the configuration
+// is loaded; an alias named like a hexa command is skipped.
+func Register() {
+	aliases, err := internalAlias.Load()
+	if err != nil {
+		loadErr = err
+		logger.Debug("Loading aliases", "error", err)
+	}
+	if len(aliases) == 0 {
+		return
+	}
+
+	cmd.RootCmd.AddGroup(&cobra.Group{ID: GroupID, Title: "Aliases:"})
+	for _, alias := range aliases {
+		if isCommand(alias.Name) {
+			shadowed = append(shadowed, alias)
+			continue
+		}
+		registered = append(registered, alias)
+		cmd.RootCmd.AddCommand(newAliasCommand(alias))
+	}
+}
+
+// isCommand reports whether name is taken by a hexa command (help and completion are added by cobra)
+func isCommand(name string) bool {
+	switch name {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, command := range cmd.RootCmd.Commands() {
+		if command.Name() == name || command.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newAliasCommand builds the command running an alias. Flags are not parsed: they are passed
+// to the aliased commands.
+func newAliasCommand(alias internalAlias.Alias) *cobra.Command {
+	return &cobra.Command{
+		Use:                alias.Usage(),
+		Short:              "Alias: hexa " + alias.String(),
+		GroupID:            GroupID,
+		DisableFlagParsing: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 && (args[0] == "-h" || args[0] == "--help") {
+				return cmd.Help()
+			}
+			return run(cmd, alias, args)
+		},
+	}
+}
+
+// run executes the steps of an alias one after the other, each as a hexa process
+func run(cmd *cobra.Command, alias internalAlias.Alias, args []string) error {
+	cmd.SilenceUsage = true
+
+	steps, err := alias.Expand(aliasArgs(cmd.Name(), args))
+	if err != nil {
+		return err
+	}
+
+	depth, _ := strconv.Atoi(os.Getenv(depthEnv))
+	if depth >= maxDepth {
+		return fmt.Errorf("alias '%s': too many nested aliases (does it call itself?)", alias.Name)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating hexa: %w", err)
+	}
+	env := childEnv(depth + 1)
+
+	for i, step := range steps {
+		if len(steps) > 1 {
+			logger.Info(fmt.Sprintf("▶️  [%d/%d] hexa %s", i+1, len(steps), strings.Join(step, " ")))
+		}
+
+		child := exec.CommandContext(cmd.Context(), executable, step...)
+		child.Stdin = os.Stdin
+		child.Stdout = cmd.OutOrStdout()
+		child.Stderr = cmd.ErrOrStderr()
+		child.Env = env
+		if err := child.Run(); err != nil {
+			if len(steps) == 1 {
+				return fmt.Errorf("❌ hexa %s: %w", alias.Name, err)
+			}
+			return fmt.Errorf("❌ hexa %s: étape %d/%d échouée (hexa %s): %w",
+				alias.Name, i+1, len(steps), strings.Join(step, " "), err)
+		}
+	}
+	return nil
+}
+
+// aliasArgs drops the global flags written before the alias name (hexa --profile x wip PROJ-1):
+// flag parsing being disabled, cobra leaves them in args, while they already apply to this run
+func aliasArgs(name string, args []string) []string {
+	for i, arg := range os.Args[1:] {
+		if arg == name && len(os.Args)-i-2 <= len(args) {
+			return os.Args[i+2:]
+		}
+	}
+	return args
+}
+
+// childEnv passes the global state of this run (profile, --config, --offline) to the steps
+func childEnv(depth int) []string {
+	env := append(os.Environ(), depthEnv+"="+strconv.Itoa(depth))
+	if profile := config.ActiveProfile(); profile != "" {
+		env = append(env, config.ProfileEnv+"="+profile)
+	}
+	if path := config.ExplicitConfigFile(); path != "" {
+		env = append(env, config.ConfigEnv+"="+path)
+	}
+	if jira.IsOffline() {
+		env = append(env, config.EnvVarName("offline")+"=true")
+	}
+	return env
+}
@@ -0,0 +1,46 @@
+package alias
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured aliases",
+	Long: `List the aliases of the configuration with the commands they run.
+Aliases named like a hexa command are ignored, and reported here.
+
+Example:
+  hexa alias list`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		if len(registered) == 0 && len(shadowed) == 0 && loadErr == nil {
+			_, _ = fmt.Fprintln(out, "ℹ️  Aucun alias défini (section aliases de la configuration)")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, alias := range registered {
+			_, _ = fmt.Fprintf(w, "   %s\thexa %s\n", alias.Usage(), alias.String())
+		}
+		for _, alias := range shadowed {
+			_, _ = fmt.Fprintf(w, "   %s\tignoré: masqué par la commande hexa %s\n", alias.Name, alias.Name)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if loadErr != nil {
+			_, _ = fmt.Fprintf(out, "⚠️  %v\n", loadErr)
+		}
+		return nil
+	},
+}
+
+func init() {
+	AliasCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"github.com/hyphaene/hexa/cmd"
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.RootCmd.AddCommand(AuthCmd)
+}
+
+var AuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage stored credentials",
+	Long: `Store secrets outside of configuration files and reference them from config:
+
+  jira:
+    token: secret://jira/default
+
+Secrets are kept in an encrypted file (~/.hexa/credentials.enc) unlocked with a passphrase
+(` + credentials.PassphraseEnv + `, credentials.keyFile or a prompt), or in an external
+git-style credential helper (credentials.helper).`,
+}
+
+// secretName accepts a name with or without the secret:// scheme
+func secretName(arg string) string {
+	return credentials.Name(arg)
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a stored secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := secretName(args[0])
+
+		err := credentials.Delete(name)
+		if errors.Is(err, credentials.ErrNotFound) {
+			return fmt.Errorf("no secret stored under '%s'", name)
+		}
+		if err != nil {
+			return fmt.Errorf("deleting %s: %w", name, err)
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  Secret '%s' supprimé\n", name)
+		return nil
+	},
+}
+
+func init() {
+	AuthCmd.AddCommand(deleteCmd)
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a stored secret",
+	Long: `Print the secret stored under <service>/<account> on stdout.
+
+Example:
+  hexa auth get jira/default`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := secretName(args[0])
+
+		secret, err := credentials.Get(name)
+		if errors.Is(err, credentials.ErrNotFound) {
+			return fmt.Errorf("no secret stored under '%s'", name)
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), secret)
+		return nil
+	},
+}
+
+func init() {
+	AuthCmd.AddCommand(getCmd)
+}
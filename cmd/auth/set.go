@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret",
+	Long: `Store a secret under <service>/<account>. The secret is prompted for (not echoed),
+or read from stdin, so it never ends up in your shell history.
+
+Example:
+  hexa auth set jira/default
+  echo "$JIRA_TOKEN" | hexa auth set jira/default`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := secretName(args[0])
+
+		secret, err := credentials.ReadSecret(fmt.Sprintf("Secret for %s: ", name))
+		if err != nil {
+			return err
+		}
+		if secret == "" {
+			return fmt.Errorf("empty secret, nothing stored")
+		}
+
+		if err := credentials.Set(name, secret); err != nil {
+			return fmt.Errorf("storing %s: %w", name, err)
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Secret '%s' enregistré\n", name)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "💡 Reference it from config, e.g. token: %s%s\n", credentials.Scheme, name)
+		return nil
+	},
+}
+
+func init() {
+	AuthCmd.AddCommand(setCmd)
+}
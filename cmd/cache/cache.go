@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyphaene/hexa/cmd"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.RootCmd.AddCommand(CacheCmd)
+}
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the local cache",
+	Long:  `Inspect, clear and warm the local cache stored in ~/.hexa/cache (~/.hexa/profiles/<name>/cache with a profile).`,
+}
+
+// formatAge formats a duration for cache listings (e.g. "45s", "12m", "3.5h", "2d")
+func formatAge(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%.0fs", d.Seconds())
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// formatSize formats a byte count (e.g. "512B", "12.3KB", "1.2MB")
+func formatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%dB", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1fKB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(size)/(1024*1024))
+	}
+}
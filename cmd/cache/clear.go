@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"fmt"
+
+	internalCache "github.com/hyphaene/hexa/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clearSprintFlag  int
+	clearAllFlag     bool
+	clearExpiredFlag bool
+)
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear cached entries",
+	Long: `Clear cached entries.
+
+  --sprint N   Remove the cached tickets of sprint N (its snapshot history is kept)
+  --expired    Remove every expired entry
+  --all        Remove the whole cache directory, snapshot history included`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case clearAllFlag:
+			if err := internalCache.ClearAll(); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  Cache entièrement vidé.\n")
+		case clearSprintFlag > 0:
+			if err := internalCache.ClearSprint(clearSprintFlag); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  Tickets du sprint %d supprimés du cache.\n", clearSprintFlag)
+		case clearExpiredFlag:
+			removed, err := internalCache.ClearExpired()
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  %d entrée(s) expirée(s) supprimée(s).\n", len(removed))
+		default:
+			return fmt.Errorf("specify what to clear: --sprint N, --expired or --all")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(clearCmd)
+	clearCmd.Flags().IntVar(&clearSprintFlag, "sprint", 0, "Remove the cached tickets of a sprint ID")
+	clearCmd.Flags().BoolVar(&clearAllFlag, "all", false, "Remove the whole cache directory")
+	clearCmd.Flags().BoolVar(&clearExpiredFlag, "expired", false, "Remove expired entries only")
+	clearCmd.MarkFlagsMutuallyExclusive("sprint", "all", "expired")
+}
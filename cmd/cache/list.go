@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	internalCache "github.com/hyphaene/hexa/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached entries with their size, age and expiry",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		infos, err := internalCache.List()
+		if err != nil {
+			return err
+		}
+
+		if len(infos) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "📭 Cache vide.\n")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAMESPACE\tKEY\tSIZE\tAGE\tEXPIRY")
+
+		var totalSize int64
+		for _, info := range infos {
+			expiry := "never"
+			if !info.ExpiresAt.IsZero() {
+				if info.IsExpired() {
+					expiry = fmt.Sprintf("expired %s ago", formatAge(time.Since(info.ExpiresAt)))
+				} else {
+					expiry = fmt.Sprintf("in %s", formatAge(time.Until(info.ExpiresAt)))
+				}
+			}
+			totalSize += info.Size
+
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				info.Namespace, info.Key, formatSize(info.Size), formatAge(time.Since(info.CachedAt)), expiry)
+		}
+		_ = w.Flush()
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n📊 %d entrée(s), %s\n", len(infos), formatSize(totalSize))
+		return nil
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"fmt"
+
+	internalCache "github.com/hyphaene/hexa/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the cache directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := internalCache.Dir()
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), dir)
+		return nil
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(pathCmd)
+}
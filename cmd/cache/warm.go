@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	internalCache "github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var warmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Prefetch Jira data into the cache",
+	Long: `Prefetch the board ID, current sprint, sprint tickets, user profile, statuses
+and the transitions of your tickets, e.g. before going offline.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jira.IsOffline() {
+			return fmt.Errorf("cannot warm the cache in offline mode")
+		}
+
+		internalCache.SetRefresh(true)
+		defer internalCache.SetRefresh(false)
+
+		ctx := cmd.Context()
+		out := cmd.OutOrStdout()
+
+		boardID, err := internalCache.BoardID(ctx)
+		if err != nil {
+			return fmt.Errorf("resolving board ID: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "✅ Board: %d\n", boardID)
+
+		sprintID, err := internalCache.CurrentSprintID(ctx)
+		if err != nil {
+			return fmt.Errorf("getting current sprint ID: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "✅ Current sprint: %d\n", sprintID)
+		if err := internalCache.RecordActiveSprint(sprintID, time.Now()); err != nil {
+			logger.Debug("Recording active sprint", "error", err)
+		}
+
+		previous, _ := internalCache.ReadCache(sprintID) // A corrupted entry means a full refresh
+		entry, err := internalCache.RefreshSprint(ctx, sprintID, time.Now(), internalCache.IncrementalFetcher(previous, func(ctx context.Context) ([]jira.Ticket, int, error) {
+			return jira.FetchSprintTickets(ctx, sprintID)
+		}))
+		if entry == nil {
+			return fmt.Errorf("fetching sprint tickets: %w", err)
+		}
+		if err != nil {
+			return fmt.Errorf("writing sprint cache: %w", err)
+		}
+		tickets := entry.Issues
+		_, _ = fmt.Fprintf(out, "✅ Sprint tickets: %d\n", entry.Total)
+
+		profile, err := internalCache.CurrentUser(ctx)
+		if err != nil {
+			return fmt.Errorf("fetching user profile: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "✅ User: %s\n", profile.DisplayName)
+
+		// Statuses are optional: some instances restrict /status to admins
+		if statuses, err := internalCache.Statuses(ctx); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  Statuses: %v\n", err)
+		} else {
+			_, _ = fmt.Fprintf(out, "✅ Statuses: %d\n", len(statuses))
+		}
+
+		// Transitions of my tickets, so they can be moved offline (queued in the outbox)
+		mine := jira.FilterByAssignee(tickets, "me", *profile)
+		warmed := 0
+		for _, ticket := range mine {
+			if _, err := internalCache.Transitions(ctx, ticket.Key); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  Transitions of %s: %v\n", ticket.Key, err)
+				continue
+			}
+			warmed++
+		}
+		_, _ = fmt.Fprintf(out, "✅ Transitions: %d ticket(s)\n", warmed)
+
+		return nil
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(warmCmd)
+}
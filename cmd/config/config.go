@@ -6,6 +6,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/hyphaene/hexa/cmd"
+	"github.com/hyphaene/hexa/cmd/config/layer"
 	"github.com/hyphaene/hexa/internal/config"
 	"github.com/hyphaene/hexa/internal/env"
 	"github.com/spf13/cobra"
@@ -14,15 +15,43 @@ import (
 
 func init() {
 	cmd.RootCmd.AddCommand(ConfigCmd)
+	ConfigCmd.AddCommand(layer.New(layer.Layer{
+		Name:  "local",
+		File:  ".hexa.local.yml",
+		Short: "Manage project local secrets (.hexa.local.yml)",
+		Long: `Read and update .hexa.local.yml in the current directory: the gitignored project file
+holding secrets and personal values, which overrides the project and user configuration.`,
+		Path:         config.LocalConfigPath,
+		GetExample:   "jira.token",
+		SetExamples:  []string{`jira.token "your-pat"`, "jira.boardId 1234"},
+		UnsetExample: "jira.boardId",
+	}), layer.New(layer.Layer{
+		Name:  "user",
+		File:  "~/.hexa.yml",
+		Short: "Manage the user global config (~/.hexa.yml)",
+		Long: `Read and update ~/.hexa.yml: the global configuration shared by every project,
+overridden by project files (.hexa.yml, .hexa.local.yml).`,
+		Path:         config.UserConfigPath,
+		GetExample:   "jira.url",
+		SetExamples:  []string{"jira.url https://jira.example.com", "log.level warn"},
+		UnsetExample: "jira.boardId",
+	}))
+	addShowSecretsFlag(ConfigCmd)
 }
 
 var ConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage configuration for hexa CLI",
-	Long:  `Manage configuration for hexa CLI.`,
+	Long: `Manage configuration for hexa CLI.
+
+Without a subcommand, prints the merged configuration. Secret values (tokens, passwords,
+webhook URLs and the keys listed in the "secrets" config list) are masked unless --show-secrets is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Configuration is already loaded in the global Viper instance via main()
 		mergedConfig := config.GetMergedConfig()
+		if !showSecrets(cmd) {
+			mergedConfig = config.MaskSettings(mergedConfig)
+		}
 		mergedConfigYAML, _ := yaml.Marshal(mergedConfig)
 
 		fmt.Println("Debug Mode:", env.Debug)
@@ -33,3 +62,14 @@ var ConfigCmd = &cobra.Command{
 
 	},
 }
+
+// addShowSecretsFlag adds --show-secrets to a command printing configuration values
+func addShowSecretsFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-secrets", false, layer.ShowSecretsUsage)
+}
+
+// showSecrets reports whether --show-secrets was given
+func showSecrets(cmd *cobra.Command) bool {
+	show, _ := cmd.Flags().GetBool("show-secrets")
+	return show
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [key]",
+	Short: "Show where each configuration value comes from",
+	Long: `List every layer defining a key, in precedence order (environment, .env, the
+profiles.<name> section of the active profile, .hexa.local.yml, .hexa.yml, ~/.hexa.yml),
+and mark the one that wins.
+Without a key, every configured key is explained; a section (e.g. jira) explains its keys.
+Secrets are masked unless --show-secrets is given.
+
+Example:
+  hexa config explain jira.url`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.CompleteKeys(toComplete), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+
+		keys := config.ExplainableKeys(prefix)
+		if len(keys) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ℹ️  '%s' n'est défini dans aucune source (valeur par défaut de hexa)\n", prefix)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "   Variable d'environnement: %s\n", config.EnvVarName(prefix))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for i, key := range keys {
+			if i > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+			_, _ = fmt.Fprintf(w, "🔎 %s\n", key)
+			for j, source := range config.Explain(key) {
+				marker := "" // Trailing: emojis would break the tabwriter alignment
+				if j == 0 {
+					marker = "✅"
+				}
+				value := config.DisplayValue(key, source.Value)
+				if showSecrets(cmd) {
+					value = config.FormatValue(source.Value)
+				}
+				_, _ = fmt.Fprintf(w, "   %s\t%s\t%s\t%s\n", source.Layer, source.Location, value, marker)
+			}
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	addShowSecretsFlag(explainCmd)
+	ConfigCmd.AddCommand(explainCmd)
+}
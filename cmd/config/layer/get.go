@@ -0,0 +1,47 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newGetCmd prints a value of the file
+func newGetCmd(layer Layer) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a value of " + layer.File,
+		Long: `Print a value of ` + layer.File + `, using dotted keys for nested values.
+Secrets are masked unless --show-secrets is given.
+
+Example:
+  hexa config ` + layer.Name + ` get ` + layer.GetExample,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: layer.completeFileKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := layer.Path()
+			if err != nil {
+				return err
+			}
+
+			value, err := config.ReadYAMLField(path, args[0])
+			if errors.Is(err, config.ErrKeyNotFound) {
+				return fmt.Errorf("'%s' is not set in %s", args[0], path)
+			}
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", path, err)
+			}
+
+			output := config.DisplayValue(args[0], value)
+			if show, _ := cmd.Flags().GetBool("show-secrets"); show {
+				output = config.FormatValue(value)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), output)
+			return nil
+		},
+	}
+	command.Flags().Bool("show-secrets", false, ShowSecretsUsage)
+	return command
+}
@@ -0,0 +1,62 @@
+package layer
+
+import (
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Layer is a configuration file edited by hexa config <name> get|list|set|unset
+type Layer struct {
+	Name  string                 // Subcommand name: local, user
+	File  string                 // File as shown in the help: .hexa.local.yml, ~/.hexa.yml
+	Short string                 // Short help of the subcommand
+	Long  string                 // Long help of the subcommand
+	Path  func() (string, error) // Resolves the path of the file
+
+	GetExample   string   // Example key of get
+	SetExamples  []string // Example arguments of set
+	UnsetExample string   // Example key of unset
+}
+
+// ShowSecretsUsage is the help of --show-secrets, given to the commands printing values
+const ShowSecretsUsage = "Show secret values (tokens, passwords...) instead of masking them"
+
+// New returns the command of the layer, with its get, list, set and unset subcommands
+func New(layer Layer) *cobra.Command {
+	command := &cobra.Command{
+		Use:   layer.Name,
+		Short: layer.Short,
+		Long:  layer.Long,
+	}
+	command.AddCommand(newGetCmd(layer), newListCmd(layer), newSetCmd(layer), newUnsetCmd(layer))
+	return command
+}
+
+// completeFileKeys completes the keys already set in the file
+func (layer Layer) completeFileKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	path, err := layer.Path()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	fields, err := config.ListYAMLFields(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	keys := make([]string, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, field.Key)
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeKnownKeys completes the configuration keys known to hexa
+func completeKnownKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.CompleteKeys(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,48 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newListCmd lists the values of the file
+func newListCmd(layer Layer) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the values of " + layer.File,
+		Long:  "List the values of the file. Secrets are masked unless --show-secrets is given.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := layer.Path()
+			if err != nil {
+				return err
+			}
+
+			fields, err := config.ListYAMLFields(path)
+			if errors.Is(err, os.ErrNotExist) {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ℹ️  %s n'existe pas\n", path)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", path, err)
+			}
+
+			show, _ := cmd.Flags().GetBool("show-secrets")
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "📄 %s\n", path)
+			for _, field := range fields {
+				value := config.DisplayValue(field.Key, field.Value)
+				if show {
+					value = config.FormatValue(field.Value)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s = %s\n", field.Key, value)
+			}
+			return nil
+		},
+	}
+	command.Flags().Bool("show-secrets", false, ShowSecretsUsage)
+	return command
+}
@@ -0,0 +1,48 @@
+package layer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newSetCmd sets a value in the file
+func newSetCmd(layer Layer) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a value in " + layer.File,
+		Long: `Set a value in ` + layer.File + ` (created if missing), keeping comments and other values.
+Values are typed: true/false are booleans, 42 is an integer, [a, b] is a list, except for
+keys hexa reads as strings (jira.token, user.me...), which keep the value as given.
+Use --string to store the value as a string anyway, e.g. a numeric value of an unknown key.
+
+Example:
+  hexa config ` + layer.Name + ` set ` + strings.Join(layer.SetExamples, "\n  hexa config "+layer.Name+" set "),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeKnownKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := layer.Path()
+			if err != nil {
+				return err
+			}
+
+			var value any = args[1]
+			if asString, _ := cmd.Flags().GetBool("string"); !asString {
+				if value, err = config.ParseKeyValue(args[0], args[1]); err != nil {
+					return err
+				}
+			}
+
+			if err := config.UpdateYAMLField(path, args[0], value); err != nil {
+				return fmt.Errorf("updating %s: %w", path, err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ %s mis à jour dans %s\n", args[0], path)
+			return nil
+		},
+	}
+	command.Flags().Bool("string", false, "Store the value as a string, without type inference")
+	return command
+}
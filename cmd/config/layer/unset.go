@@ -0,0 +1,40 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newUnsetCmd removes a value from the file
+func newUnsetCmd(layer Layer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a value from " + layer.File,
+		Long: `Remove a value from ` + layer.File + `. Sections left empty are removed too.
+
+Example:
+  hexa config ` + layer.Name + ` unset ` + layer.UnsetExample,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: layer.completeFileKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := layer.Path()
+			if err != nil {
+				return err
+			}
+
+			err = config.UnsetYAMLField(path, args[0])
+			if errors.Is(err, config.ErrKeyNotFound) {
+				return fmt.Errorf("'%s' is not set in %s", args[0], path)
+			}
+			if err != nil {
+				return fmt.Errorf("updating %s: %w", path, err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  %s supprimé de %s\n", args[0], path)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration files to the current format",
+	Long: `Apply the pending format migrations (renamed keys, moved sections, changed types)
+to ~/.hexa.yml, .hexa.yml and .hexa.local.yml, and stamp them with the current version.
+
+Comments and layout are preserved, and each original file is copied to
+~/.hexa/backups before being rewritten. Use --dry-run to only list the changes.
+
+Example:
+  hexa config migrate --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		paths := config.ConfigFiles()
+		if len(paths) == 0 {
+			_, _ = fmt.Fprintln(out, "ℹ️  Aucun fichier de configuration trouvé")
+			return nil
+		}
+
+		var errs []error
+		for _, path := range paths {
+			result, err := config.MigrateFile(path, migrateDryRun)
+			switch {
+			case err != nil:
+				errs = append(errs, err)
+				continue
+			case result == nil:
+				continue // Empty file
+			case result.UpToDate():
+				_, _ = fmt.Fprintf(out, "✅ %s: à jour (version %d)\n", path, result.To)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(out, "🔄 %s: version %d → %d\n", path, result.From, result.To)
+			for _, change := range result.Changes {
+				_, _ = fmt.Fprintf(out, "   • %s\n", change)
+			}
+			if result.Backup != "" {
+				_, _ = fmt.Fprintf(out, "   💾 Sauvegarde: %s\n", result.Backup)
+			}
+		}
+
+		if migrateDryRun {
+			_, _ = fmt.Fprintln(out, "🔍 Dry run: aucun fichier modifié")
+		}
+		if err := errors.Join(errs...); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the changes without writing the files")
+	ConfigCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+)
+
+// prompter asks the questions of hexa config setup
+type prompter struct {
+	in         *bufio.Reader
+	out        io.Writer
+	readSecret func(prompt string) (string, error) // Masked input, from the terminal
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out, readSecret: credentials.ReadSecret}
+}
+
+// secret reads an answer without echoing it
+func (p *prompter) secret(label string) (string, error) {
+	return p.readSecret(label)
+}
+
+// ask reads a line, returning fallback when it is empty. A closed input is an error,
+// so that a wizard fed by a pipe never loops forever.
+func (p *prompter) ask(label, fallback string) (string, error) {
+	if fallback != "" {
+		_, _ = fmt.Fprintf(p.out, "%s [%s]: ", label, fallback)
+	} else {
+		_, _ = fmt.Fprintf(p.out, "%s: ", label)
+	}
+
+	line, err := p.in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", fmt.Errorf("setup aborted: no more input")
+		}
+		return "", fmt.Errorf("reading answer: %w", err)
+	}
+	if line == "" {
+		return fallback, nil
+	}
+	return line, nil
+}
+
+// askValid asks until check accepts the answer
+func (p *prompter) askValid(label, fallback string, check func(string) error) (string, error) {
+	for {
+		answer, err := p.ask(label, fallback)
+		if err != nil {
+			return "", err
+		}
+		if err := check(answer); err != nil {
+			_, _ = fmt.Fprintf(p.out, "   ❌ %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// confirm asks a yes/no question (o/oui/y/yes)
+func (p *prompter) confirm(label string, fallback bool) (bool, error) {
+	choices := "o/N"
+	if fallback {
+		choices = "O/n"
+	}
+	for {
+		answer, err := p.ask(fmt.Sprintf("%s (%s)", label, choices), "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return fallback, nil
+		case "o", "oui", "y", "yes":
+			return true, nil
+		case "n", "non", "no":
+			return false, nil
+		}
+	}
+}
+
+// choose asks for one of choices
+func (p *prompter) choose(label string, choices []string, fallback string) (string, error) {
+	return p.askValid(fmt.Sprintf("%s (%s)", label, strings.Join(choices, "|")), fallback, func(answer string) error {
+		for _, choice := range choices {
+			if strings.EqualFold(answer, choice) {
+				return nil
+			}
+		}
+		return fmt.Errorf("choisir parmi %s", strings.Join(choices, ", "))
+	})
+}
@@ -1,15 +1,36 @@
 package config
 
 import (
+	"context"
 	_ "embed"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/credentials"
+	internalJira "github.com/hyphaene/hexa/internal/jira"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// boardListLimit is the number of boards shown per search
+const boardListLimit = 20
+
+var (
+	setupIfMissing bool
+	setupForce     bool
 )
 
 func init() {
+	SetupCmd.Flags().BoolVar(&setupIfMissing, "if-missing", false, "Non-interactive: create ~/.hexa.yml from the template only if it does not exist")
+	SetupCmd.Flags().BoolVar(&setupForce, "force", false, "Non-interactive: overwrite ~/.hexa.yml with the template (the current file is backed up)")
+	SetupCmd.MarkFlagsMutuallyExclusive("if-missing", "force")
 	ConfigCmd.AddCommand(SetupCmd)
 }
 
@@ -19,49 +40,362 @@ var template []byte
 var SetupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Setup and initialize hexa CLI",
-	Long:  `Setup and initialize hexa CLI.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Setup logic here
-		userHomeDir, _ := os.UserHomeDir()
-		filePath := filepath.Join(userHomeDir, ".hexa.yml")
-		_, err := os.Stat(filePath)
-		if err == nil {
-			println("User config file already exists at", filePath)
-			return
-		}
-		if errors.Is(err, os.ErrNotExist) {
-			println("Creating user config file at", filePath)
-			if writeErr := os.WriteFile(filePath, template, 0644); writeErr != nil {
-				println("Failed to create user config file:", writeErr.Error())
-				return
+	Long: `Guided setup of hexa:
+  1. ~/.hexa.yml, created from the template
+  2. the current project: your name, Jira URL and authentication, token checked
+     against Jira, board picked from a searchable list
+  3. everything saved to .hexa.local.yml, added to .gitignore
+
+The token can be stored in the encrypted credentials store (secret://jira/default, or
+secret://jira/<profile> with --profile) instead of in clear text. With a profile, the project
+settings are written to its section (profiles.<profile>).
+
+--if-missing and --force only install the template, without any question
+(e.g. from a Homebrew post-install).
+
+Example:
+  hexa config setup
+  hexa config setup --if-missing`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		userPath, err := config.UserConfigPath()
+		if err != nil {
+			return err
+		}
+
+		if setupIfMissing || setupForce {
+			return installTemplate(out, userPath, setupForce)
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("hexa config setup is interactive: run it in a terminal, or use --if-missing / --force")
+		}
+
+		p := newPrompter(cmd.InOrStdin(), out)
+		_, _ = fmt.Fprintln(out, "🧭 Configuration guidée de hexa (Entrée = valeur par défaut)")
+		_, _ = fmt.Fprintln(out)
+
+		if err := setupUserFile(p, out, userPath); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(out)
+		project, err := p.confirm("Configurer le projet courant (.hexa.local.yml, ignoré par git) ?", true)
+		if err != nil {
+			return err
+		}
+		if project {
+			if err := setupProject(cmd.Context(), p, out); err != nil {
+				return err
 			}
-			return
-		}
-		println("Error checking user config file:", err.Error())
-
-		// create the file from template
-		// write to ./hexa.local.yml
-		// check if .gitignore exists
-		// - if yes, append .hexa.local.yml if not already present
-		// - if no, create .gitignore with .hexa.local.yml inside
-
-		// en mode interactif :
-		// un truc dans le genre
-
-		// voulez vous créer un fichier de config global ? (y/n)
-		// check si existe
-		// - si oui, dire qu'il existe deja
-		// => écraser ou skip
-		// - si non, le créer
-		// voulez vous créer un fichier de config local ? (y/n)
-		// ici on va : chercher le pwd
-		// - creer le .hexa.local.yml ?
-		// voir si y'a un fichier gitignore
-		// - si oui, ajouter .hexa.local.yml dedans
-		// - si non, creer un fichier gitignore avec .hexa.local.yml dedans
-		// - ajouter les infos de base dans le .hexa.local.yml ( user.me: xxx)
-		// - dire a l'utilisateur de modifier le fichier pour ajouter son jira token et son jira domain
-		// - lui dire de relancer la commande pour initialiser le reste
+		}
 
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, "🎉 Configuration terminée. Vérifiez-la avec 'hexa config validate', puis essayez 'hexa jira sprint pulse'.")
+		return nil
 	},
 }
+
+// installTemplate writes the template to the user config file: only if it is missing,
+// or over it (after a backup) with force
+func installTemplate(out io.Writer, path string, force bool) error {
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil && !force:
+		_, _ = fmt.Fprintf(out, "✅ %s existe déjà (rien à faire)\n", path)
+		return nil
+	case err == nil:
+		backup, err := config.BackupFile(path, data)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "💾 Sauvegarde: %s\n", backup)
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("checking %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, template, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	_, _ = fmt.Fprintf(out, "✅ %s initialisé depuis le modèle\n", path)
+	return nil
+}
+
+// setupUserFile offers to create (or replace) ~/.hexa.yml from the template
+func setupUserFile(p *prompter, out io.Writer, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		_, _ = fmt.Fprintf(out, "ℹ️  %s existe déjà\n", path)
+		replace, err := p.confirm("Le remplacer par le modèle (une sauvegarde est faite) ?", false)
+		if err != nil || !replace {
+			return err
+		}
+		return installTemplate(out, path, true)
+	}
+
+	create, err := p.confirm(fmt.Sprintf("Créer %s depuis le modèle ?", path), true)
+	if err != nil || !create {
+		return err
+	}
+	return installTemplate(out, path, false)
+}
+
+// setupProject asks for the project settings, checks the token against Jira, and writes
+// them to .hexa.local.yml
+func setupProject(ctx context.Context, p *prompter, out io.Writer) error {
+	localPath, err := config.LocalConfigPath()
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "📁 Fichier du projet: %s\n", localPath)
+
+	// With an active profile, its section would win over top-level keys: write into it (like hexa jira init)
+	prefix := ""
+	if profile := config.ActiveProfile(); profile != "" {
+		prefix = config.ProfilesKey + "." + profile + "."
+		_, _ = fmt.Fprintf(out, "👤 Profil %s: réglages écrits sous %s\n", profile, strings.TrimSuffix(prefix, "."))
+	}
+
+	var fields []config.Field
+	set := func(key string, value any) {
+		viper.Set(key, value) // Used right away to call Jira
+		fields = append(fields, config.Field{Key: prefix + key, Value: value})
+	}
+
+	me, err := p.ask("Votre nom (user.me)", firstNonEmpty(viper.GetString("user.me"), os.Getenv("USER")))
+	if err != nil {
+		return err
+	}
+	if me != "" {
+		set("user.me", me)
+	}
+
+	jiraURL, err := p.askValid("URL de Jira (jira.url)", viper.GetString("jira.url"), func(answer string) error {
+		if answer == "" {
+			return fmt.Errorf("l'URL de Jira est requise")
+		}
+		return config.CheckKey("jira.url", answer)
+	})
+	if err != nil {
+		return err
+	}
+	set("jira.url", strings.TrimSuffix(jiraURL, "/"))
+
+	authType, err := p.choose("Authentification (jira.auth.type)", internalJira.AuthTypes, internalJira.AuthType())
+	if err != nil {
+		return err
+	}
+	set("jira.auth.type", strings.ToLower(authType))
+
+	switch internalJira.AuthType() {
+	case internalJira.AuthBasic:
+		username, err := p.askValid("Nom d'utilisateur (jira.auth.username)", viper.GetString("jira.auth.username"), required)
+		if err != nil {
+			return err
+		}
+		set("jira.auth.username", username)
+	case internalJira.AuthCloud:
+		email, err := p.askValid("Email du compte Atlassian (jira.auth.email)",
+			firstNonEmpty(viper.GetString("jira.auth.email"), viper.GetString("jira.userEmail")), required)
+		if err != nil {
+			return err
+		}
+		set("jira.auth.email", email)
+	case internalJira.AuthOAuth:
+		if err := writeSetupFields(out, localPath, fields); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out, "💡 OAuth: configurez jira.oauth.clientId, puis lancez 'hexa jira login' et 'hexa jira init'.")
+		return nil
+	}
+
+	token, profile, err := askToken(ctx, p, out)
+	if err != nil {
+		return err
+	}
+	if profile.EmailAddress != "" {
+		set("jira.userEmail", profile.EmailAddress)
+	}
+	if profile.AccountID != "" {
+		set("jira.accountId", profile.AccountID)
+	}
+
+	secretName := setupSecretName()
+	store, err := p.confirm(fmt.Sprintf("Chiffrer le token dans le coffre hexa (secret://%s) plutôt qu'en clair ?", secretName), true)
+	if err != nil {
+		return err
+	}
+	tokenValue := token
+	if store {
+		if err := credentials.Set(secretName, token); err != nil {
+			_, _ = fmt.Fprintf(out, "   ⚠️  Coffre indisponible (%v): token enregistré en clair\n", err)
+		} else {
+			tokenValue = credentials.Scheme + secretName
+		}
+	}
+	fields = append(fields, config.Field{Key: prefix + "jira.token", Value: tokenValue})
+
+	board, err := selectBoard(ctx, p, out)
+	if err != nil {
+		return err
+	}
+	if board != nil {
+		set("jira.boardId", board.ID)
+		set("jira.boardName", board.Name)
+	}
+
+	return writeSetupFields(out, localPath, fields)
+}
+
+// askToken asks for the Jira token until Jira accepts it (checked with the myself endpoint)
+func askToken(ctx context.Context, p *prompter, out io.Writer) (string, *internalJira.UserProfile, error) {
+	for {
+		token, err := p.secret("Token Jira (jira.token, masqué): ")
+		if err != nil {
+			return "", nil, err
+		}
+		if token = strings.TrimSpace(token); token == "" {
+			_, _ = fmt.Fprintln(out, "   ❌ Le token est requis")
+			continue
+		}
+
+		viper.Set("jira.token", token)
+		_, _ = fmt.Fprintln(out, "🔍 Vérification du token auprès de Jira...")
+		profile, err := internalJira.FetchCurrentUser(ctx)
+		if err == nil {
+			_, _ = fmt.Fprintf(out, "✅ Connecté en tant que %s\n", firstNonEmpty(profile.DisplayName, profile.Name, profile.EmailAddress))
+			return token, profile, nil
+		}
+
+		_, _ = fmt.Fprintf(out, "   ❌ Jira refuse le token: %v\n", err)
+		retry, err := p.confirm("Réessayer ?", true)
+		if err != nil {
+			return "", nil, err
+		}
+		if !retry {
+			return "", nil, fmt.Errorf("setup aborted: the Jira token could not be checked")
+		}
+	}
+}
+
+// selectBoard searches the boards by name and lets the user pick one (nil to skip)
+func selectBoard(ctx context.Context, p *prompter, out io.Writer) (*internalJira.Board, error) {
+	query, err := p.ask("Rechercher votre board (partie du nom, vide = tous, - pour passer)", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if query == "-" {
+			_, _ = fmt.Fprintln(out, "💡 Board ignoré: lancez 'hexa jira init' plus tard")
+			return nil, nil
+		}
+
+		boards, err := internalJira.ListBoards(ctx, query, boardListLimit)
+		switch {
+		case err != nil:
+			_, _ = fmt.Fprintf(out, "   ❌ Recherche impossible: %v\n", err)
+		case len(boards) == 0:
+			_, _ = fmt.Fprintf(out, "   Aucun board ne contient '%s'\n", query)
+		default:
+			for i, board := range boards {
+				_, _ = fmt.Fprintf(out, "   %2d. %s (%s, id %d)\n", i+1, board.Name, firstNonEmpty(board.Location.ProjectKey, board.Type), board.ID)
+			}
+			if len(boards) == boardListLimit {
+				_, _ = fmt.Fprintln(out, "   … affinez la recherche pour voir les autres boards")
+			}
+		}
+
+		answer, err := p.ask("Numéro du board, ou nouvelle recherche (- pour passer)", "")
+		if err != nil {
+			return nil, err
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(boards) {
+			board := boards[n-1]
+			_, _ = fmt.Fprintf(out, "✅ Board: %s (id %d)\n", board.Name, board.ID)
+			return &board, nil
+		}
+		query = answer
+	}
+}
+
+// setupSecretName returns the credentials store entry of the token entered in hexa config setup:
+// jira/default, or jira/<profile> with an active profile
+func setupSecretName() string {
+	if profile := config.ActiveProfile(); profile != "" {
+		return "jira/" + profile
+	}
+	return "jira/default"
+}
+
+// writeSetupFields writes the answers to the local config file and keeps it out of git
+func writeSetupFields(out io.Writer, path string, fields []config.Field) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fields = append([]config.Field{{Key: "version", Value: config.CurrentVersion}}, fields...)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+		}
+	}
+
+	// May hold a token: written at once, never readable by others
+	if err := config.UpdateYAMLFields(path, fields, 0600); err != nil {
+		return fmt.Errorf("updating %s: %w", path, err)
+	}
+	_, _ = fmt.Fprintf(out, "✅ Configuration enregistrée dans %s\n", path)
+
+	return ensureGitignored(out, path)
+}
+
+// ensureGitignored adds the local config file to the .gitignore of the project directory
+// (created if missing), unless a line already covers it
+func ensureGitignored(out io.Writer, localPath string) error {
+	dir := filepath.Dir(localPath)
+	if filepath.Base(dir) == config.ProjectDirName {
+		dir = filepath.Dir(dir) // .hexa/config.local.yml
+	}
+	entry, err := filepath.Rel(dir, localPath)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", localPath, err)
+	}
+	entry = filepath.ToSlash(entry)
+
+	gitignore := filepath.Join(dir, ".gitignore")
+	data, err := os.ReadFile(gitignore)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", gitignore, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		switch strings.TrimSpace(line) {
+		case entry, "/" + entry, "*.local.yml":
+			return nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += entry + "\n"
+	if err := os.WriteFile(gitignore, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", gitignore, err)
+	}
+	_, _ = fmt.Fprintf(out, "🙈 %s ajouté à %s\n", entry, gitignore)
+	return nil
+}
+
+// required rejects empty answers
+func required(answer string) error {
+	if answer == "" {
+		return fmt.Errorf("une valeur est requise")
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+	"github.com/spf13/viper"
+)
+
+// setupEnv isolates HOME and a project directory, loads the configuration with profile and
+// returns the project directory
+func setupEnv(t *testing.T, userConfig, profile string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if userConfig != "" {
+		if err := os.WriteFile(filepath.Join(home, ".hexa.yml"), []byte(userConfig), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	projectDir := t.TempDir()
+	projectPath := filepath.Join(projectDir, config.ProjectFileName)
+	if err := os.WriteFile(projectPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config.SetConfigFile(projectPath)
+	config.SetProfile(profile)
+	t.Cleanup(func() {
+		config.SetConfigFile("")
+		config.SetProfile("")
+		viper.Reset()
+		_ = config.Initialize() // Clears the active profile
+	})
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	return projectDir
+}
+
+// scriptedPrompter answers the questions with lines, masked ones included
+func scriptedPrompter(out *bytes.Buffer, lines ...string) *prompter {
+	p := newPrompter(strings.NewReader(strings.Join(lines, "\n")+"\n"), out)
+	p.readSecret = func(prompt string) (string, error) {
+		return p.ask(prompt, "")
+	}
+	return p
+}
+
+func TestSetupProject(t *testing.T) {
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddBoard(7, "Team Rocket")
+	projectDir := setupEnv(t, "", "")
+
+	var out bytes.Buffer
+	p := scriptedPrompter(&out,
+		"Jane",       // user.me
+		server.URL,   // jira.url
+		"pat",        // jira.auth.type
+		"wrong",      // rejected token
+		"o",          // retry
+		server.Token, // accepted token
+		"n",          // kept in clear text
+		"rocket",     // board search
+		"1",          // first board
+	)
+	if err := setupProject(context.Background(), p, &out); err != nil {
+		t.Fatalf("setupProject() error = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Jira refuse le token") {
+		t.Errorf("the wrong token was not reported:\n%s", out.String())
+	}
+
+	localPath := filepath.Join(projectDir, config.LocalFileName)
+	info, err := os.Stat(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("%s mode = %o, want 600", localPath, perm)
+	}
+
+	want := map[string]any{
+		"version":        config.CurrentVersion,
+		"user.me":        "Jane",
+		"jira.url":       server.URL,
+		"jira.auth.type": "pat",
+		"jira.userEmail": "jdoe@example.com",
+		"jira.token":     server.Token,
+		"jira.boardId":   7,
+		"jira.boardName": "Team Rocket",
+	}
+	for key, value := range want {
+		got, err := config.ReadYAMLField(localPath, key)
+		if err != nil || got != value {
+			t.Errorf("%s = %v (%v), want %v", key, got, err, value)
+		}
+	}
+
+	gitignore, err := os.ReadFile(filepath.Join(projectDir, ".gitignore"))
+	if err != nil || string(gitignore) != config.LocalFileName+"\n" {
+		t.Errorf(".gitignore = %q (%v), want %q", gitignore, err, config.LocalFileName+"\n")
+	}
+}
+
+func TestSetupProjectWithProfile(t *testing.T) {
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+	projectDir := setupEnv(t, "profiles:\n  work:\n    jira:\n      url: "+server.URL+"\n", "work")
+	t.Setenv(credentials.PassphraseEnv, "correct horse battery staple")
+
+	var out bytes.Buffer
+	p := scriptedPrompter(&out,
+		"Jane",       // user.me
+		"",           // jira.url of the profile
+		"pat",        // jira.auth.type
+		server.Token, // token
+		"o",          // stored in the credentials store
+		"-",          // no board
+	)
+	if err := setupProject(context.Background(), p, &out); err != nil {
+		t.Fatalf("setupProject() error = %v\n%s", err, out.String())
+	}
+
+	localPath := filepath.Join(projectDir, config.LocalFileName)
+	token, err := config.ReadYAMLField(localPath, "profiles.work.jira.token")
+	if err != nil || token != credentials.Scheme+"jira/work" {
+		t.Errorf("profiles.work.jira.token = %v (%v), want %s", token, err, credentials.Scheme+"jira/work")
+	}
+	if _, err := config.ReadYAMLField(localPath, "jira.token"); err == nil {
+		t.Error("jira.token written outside of the profile section")
+	}
+	if secret, err := credentials.Get("jira/work"); err != nil || secret != server.Token {
+		t.Errorf("stored secret = %q (%v), want the token", secret, err)
+	}
+}
+
+func TestWriteSetupFieldsRestrictsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, config.LocalFileName)
+	if err := os.WriteFile(path, []byte("# mine\nlog:\n  level: debug\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.local.yml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := writeSetupFields(&out, path, []config.Field{{Key: "jira.token", Value: "secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("mode = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# mine") || !strings.Contains(string(data), "level: debug") {
+		t.Errorf("existing settings lost:\n%s", data)
+	}
+	if _, err := config.ReadYAMLField(path, "version"); err == nil {
+		t.Error("version added to an existing file")
+	}
+	if entries, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(entries) > 0 {
+		t.Errorf("temporary files left: %v", entries)
+	}
+}
+
+func TestEnsureGitignored(t *testing.T) {
+	tests := []struct {
+		name      string
+		localPath string // Relative to the project directory
+		gitignore string // Initial content, "" for no .gitignore
+		want      string
+	}{
+		{name: "no gitignore", localPath: ".hexa.local.yml", want: ".hexa.local.yml\n"},
+		{name: "appended", localPath: ".hexa.local.yml", gitignore: "bin/", want: "bin/\n.hexa.local.yml\n"},
+		{name: "already ignored", localPath: ".hexa.local.yml", gitignore: "bin/\n.hexa.local.yml\n", want: "bin/\n.hexa.local.yml\n"},
+		{name: "rooted entry", localPath: ".hexa.local.yml", gitignore: "/.hexa.local.yml\n", want: "/.hexa.local.yml\n"},
+		{name: "pattern", localPath: ".hexa.local.yml", gitignore: "*.local.yml\n", want: "*.local.yml\n"},
+		{name: "directory form", localPath: ".hexa/config.local.yml", want: ".hexa/config.local.yml\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gitignore := filepath.Join(dir, ".gitignore")
+			if tt.gitignore != "" {
+				if err := os.WriteFile(gitignore, []byte(tt.gitignore), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var out bytes.Buffer
+			if err := ensureGitignored(&out, filepath.Join(dir, tt.localPath)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(gitignore)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf(".gitignore = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   string // Current ~/.hexa.yml, "" if missing
+		force      bool
+		want       string
+		wantBackup bool
+	}{
+		{name: "missing", want: string(template)},
+		{name: "if missing keeps the file", existing: "log:\n  level: debug\n", want: "log:\n  level: debug\n"},
+		{name: "force replaces it", existing: "log:\n  level: debug\n", force: true, want: string(template), wantBackup: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			path := filepath.Join(home, ".hexa.yml")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var out bytes.Buffer
+			if err := installTemplate(&out, path, tt.force); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("~/.hexa.yml =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			backups, _ := filepath.Glob(filepath.Join(home, config.BackupDirName, "*.bak"))
+			if (len(backups) > 0) != tt.wantBackup {
+				t.Fatalf("backups = %v, want backup %v", backups, tt.wantBackup)
+			}
+			if tt.wantBackup {
+				if data, _ := os.ReadFile(backups[0]); string(data) != tt.existing {
+					t.Errorf("backup = %q, want %q", data, tt.existing)
+				}
+			}
+		})
+	}
+}
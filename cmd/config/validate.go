@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration files against the known keys",
+	Long: `Check ~/.hexa.yml, .hexa.yml, .hexa.local.yml and the HEXA_* environment variables:
+unknown keys (typos like jira.bordId), wrong types, invalid values (jira.url without
+http(s)://, jira.auth.type not in pat|basic|cloud|oauth...), deprecated keys, and the
+required keys of the merged configuration (jira.url, jira.token).
+
+Issues are reported as file:line:column; the command fails if any error is found.
+
+Example:
+  hexa config validate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issues := config.Validate()
+
+		errors, warnings := 0, 0
+		for _, issue := range issues {
+			if issue.Severity == config.SeverityError {
+				errors++
+			} else {
+				warnings++
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), issue.String())
+		}
+
+		if errors > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("❌ Configuration invalide: %d erreur(s), %d avertissement(s)", errors, warnings)
+		}
+		if warnings > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  Configuration valide avec %d avertissement(s)\n", warnings)
+			return nil
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "✅ Configuration valide")
+		return nil
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(validateCmd)
+}
@@ -45,7 +45,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("🔍 Resolving board ID for '%s'...\n", boardName)
 
 	// Résoudre le Board ID via API
-	boardID, err := internalJira.GetBoardIdFromName(boardName)
+	boardID, err := internalJira.GetBoardIdFromName(cmd.Context(), boardName)
 	if err != nil {
 		return fmt.Errorf("failed to resolve board ID: %w", err)
 	}
@@ -75,14 +75,18 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Écrire jira.boardId avec notation pointée (préserve les autres champs de jira)
-	if err := config.UpdateYAMLField(absPath, "jira.boardId", boardID); err != nil {
+	// Écrire jira.boardId avec notation pointée (préserve les autres champs de jira).
+	// Avec un profil actif, sa section l'emporterait sur jira.boardId: on écrit dans le profil.
+	key := "jira.boardId"
+	if profile := config.ActiveProfile(); profile != "" {
+		key = config.ProfilesKey + "." + profile + "." + key
+	}
+	if err := config.UpdateYAMLField(absPath, key, boardID); err != nil {
 		return fmt.Errorf("updating config file: %w", err)
 	}
 
 	fmt.Printf("✅ Configuration saved to: %s\n", absPath)
-	fmt.Printf("   jira:\n")
-	fmt.Printf("     boardId: %d\n", boardID)
+	fmt.Printf("   %s: %d\n", key, boardID)
 	fmt.Println()
 	fmt.Println("💡 Tip: Run 'hexa jira refresh' if the board ID becomes stale.")
 
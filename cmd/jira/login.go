@@ -0,0 +1,91 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+
+	internalJira "github.com/hyphaene/hexa/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+// loginTimeout bounds the wait for the browser callback when --timeout is not set
+const loginTimeout = 5 * time.Minute
+
+var noBrowser bool
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to Jira with OAuth 2.0",
+	Long: `Opens the Jira authorization page in your browser (OAuth 2.0 authorization code with PKCE)
+and stores the access and refresh tokens under ~/.hexa/oauth (~/.hexa/profiles/<name>/oauth with a
+profile), so no personal access token is needed.
+The access token is refreshed automatically when it expires.
+
+Requires an OAuth application (jira.oauth.clientId) allowing the redirect URL
+` + internalJira.DefaultRedirectURL + ` (or jira.oauth.redirectUrl), and jira.auth.type: oauth.
+
+Example:
+  hexa jira login`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, loginTimeout)
+			defer cancel()
+		}
+
+		out := cmd.OutOrStdout()
+		_, err := internalJira.Login(ctx, func(authURL string) {
+			_, _ = fmt.Fprintf(out, "🌐 Ouvrez cette URL pour vous connecter à Jira :\n\n  %s\n\n", authURL)
+			if !noBrowser {
+				if err := openBrowser(authURL); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  Impossible d'ouvrir le navigateur: %v\n", err)
+				}
+			}
+			_, _ = fmt.Fprintf(out, "⏳ En attente de l'autorisation...\n")
+		})
+		if err != nil {
+			return fmt.Errorf("OAuth login failed: %w", err)
+		}
+
+		if internalJira.AuthType() != internalJira.AuthOAuth {
+			_, _ = fmt.Fprintf(out, "✅ Jetons OAuth enregistrés\n")
+			_, _ = fmt.Fprintf(out, "💡 Set jira.auth.type: oauth in your config to use them instead of jira.token.\n")
+			return nil
+		}
+
+		profile, err := internalJira.FetchCurrentUser(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("checking the OAuth session: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "✅ Connecté en tant que %s\n", profile.DisplayName)
+		return nil
+	},
+}
+
+func init() {
+	loginCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Only print the authorization URL")
+	JiraCmd.AddCommand(loginCmd)
+}
+
+// openBrowser opens url with the platform's default browser
+func openBrowser(url string) error {
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", url)
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		command = exec.Command("xdg-open", url)
+	}
+	if err := command.Start(); err != nil {
+		return err
+	}
+	go func() { _ = command.Wait() }()
+	return nil
+}
@@ -0,0 +1,34 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+
+	internalJira "github.com/hyphaene/hexa/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Revoke and delete the Jira OAuth tokens",
+	Long: `Revokes the OAuth tokens stored by 'hexa jira login' (when the provider supports it,
+e.g. Jira Data Center) and deletes them. Tokens are deleted even if Jira cannot be reached.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := internalJira.Logout(cmd.Context())
+		if errors.Is(err, internalJira.ErrNotLoggedIn) {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ℹ️  Aucune session OAuth pour cette instance Jira\n")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Déconnecté: jetons OAuth révoqués et supprimés\n")
+		return nil
+	},
+}
+
+func init() {
+	JiraCmd.AddCommand(logoutCmd)
+}
@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"fmt"
+
+	internalOutbox "github.com/hyphaene/hexa/internal/outbox"
+	"github.com/spf13/cobra"
+)
+
+var dropAllFlag bool
+
+var dropCmd = &cobra.Command{
+	Use:   "drop <id>...",
+	Short: "Discard queued Jira operations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !dropAllFlag {
+			return fmt.Errorf("specify operation ids or --all")
+		}
+
+		ops, err := selectOperations(args)
+		if err != nil {
+			return err
+		}
+
+		for _, op := range ops {
+			if err := internalOutbox.Drop(op.ID); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  Dropped %s\n", op.Describe())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	OutboxCmd.AddCommand(dropCmd)
+	dropCmd.Flags().BoolVar(&dropAllFlag, "all", false, "Drop every queued operation")
+	dropCmd.ValidArgsFunction = completeOperationIDs
+}
@@ -0,0 +1,43 @@
+package outbox
+
+import (
+	"fmt"
+
+	internalOutbox "github.com/hyphaene/hexa/internal/outbox"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued Jira operations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ops, err := internalOutbox.List()
+		if err != nil {
+			return err
+		}
+
+		if len(ops) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "📭 Aucune opération en attente.\n")
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "📮 %d opération(s) en attente:\n\n", len(ops))
+		for _, op := range ops {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n    %s (queued %s)\n",
+				op.ID, op.Describe(), op.QueuedAt.Local().Format("2006-01-02 15:04"))
+			if op.Uncertain {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    ❓ uncertain: Jira may have applied it, check before 'replay --force'\n")
+			}
+			if op.LastError != "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    ⚠️  last error: %s\n", op.LastError)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	OutboxCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,19 @@
+package outbox
+
+import (
+	"github.com/hyphaene/hexa/cmd/jira"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	jira.JiraCmd.AddCommand(OutboxCmd)
+}
+
+var OutboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Manage Jira operations queued while offline",
+	Long: `Jira write operations (comment, move, assign, label) made while Jira is unreachable
+are stored in ~/.hexa/outbox (~/.hexa/profiles/<name>/outbox with a profile). Use these
+commands to list, send or drop them.`,
+}
@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	internalOutbox "github.com/hyphaene/hexa/internal/outbox"
+	"github.com/spf13/cobra"
+)
+
+var forceFlag bool
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [id]...",
+	Short: "Send queued Jira operations",
+	Long: `Send queued Jira operations in the order they were made (all of them if no id is given).
+
+Before sending, each operation is checked against the current ticket status: if the ticket
+changed since the operation was queued, it is kept in the outbox as a conflict. A move to the
+status the ticket already has is skipped and removed from the outbox. Only the status is
+compared: comments, assignee or labels changed in the meantime are not conflicts.
+
+If Jira stops answering after receiving an operation, it may have been applied: the operation
+is kept as uncertain and not sent again, so that a comment is never posted twice.
+Use --force to send conflicting or uncertain operations anyway.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ops, err := selectOperations(args)
+		if err != nil {
+			return err
+		}
+
+		sent, skipped, conflicts, uncertain, failed := 0, 0, 0, 0, 0
+		for i := range ops {
+			op := &ops[i]
+			err := internalOutbox.Replay(cmd.Context(), op, forceFlag)
+			switch {
+			case err == nil:
+				sent++
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ %s\n", op.Describe())
+			case errors.Is(err, internalOutbox.ErrSkipped):
+				skipped++
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⏭️  %s\n    skipped: %v\n", op.Describe(), err)
+			case errors.Is(err, internalOutbox.ErrConflict):
+				conflicts++
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  %s\n    conflict: %v\n", op.Describe(), err)
+			case errors.Is(err, internalOutbox.ErrUncertain):
+				uncertain++
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "❓ %s\n    uncertain: %v\n", op.Describe(), err)
+				if cmd.Context().Err() != nil || jira.IsConnectionError(err) {
+					return fmt.Errorf("jira did not answer, %d operation(s) kept in outbox: %w", len(ops)-sent-skipped, err)
+				}
+			case cmd.Context().Err() != nil:
+				return fmt.Errorf("replay interrupted, %d operation(s) kept in outbox: %w", len(ops)-sent-skipped, err)
+			case jira.IsConnectionError(err):
+				// Still offline: keep the remaining operations for later
+				return fmt.Errorf("jira is still unreachable, %d operation(s) kept in outbox: %w", len(ops)-sent-skipped, err)
+			default:
+				failed++
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "❌ %s\n    error: %v\n", op.Describe(), err)
+			}
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n📊 %d sent, %d skipped, %d conflict(s), %d uncertain, %d failed\n", sent, skipped, conflicts, uncertain, failed)
+		if conflicts+uncertain > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "💡 Check the tickets, then use --force to send conflicting or uncertain operations, or 'hexa jira outbox drop <id>' to discard them.\n")
+		}
+		if conflicts+uncertain+failed > 0 {
+			return fmt.Errorf("%d operation(s) could not be sent", conflicts+uncertain+failed)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	OutboxCmd.AddCommand(replayCmd)
+	replayCmd.Flags().BoolVar(&forceFlag, "force", false, "Send operations even if the ticket changed since they were queued, or may already have been applied")
+	replayCmd.ValidArgsFunction = completeOperationIDs
+}
+
+// selectOperations returns the queued operations matching ids (all of them if ids is empty)
+func selectOperations(ids []string) ([]internalOutbox.Operation, error) {
+	ops, err := internalOutbox.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ops, nil
+	}
+
+	selected := make([]internalOutbox.Operation, 0, len(ids))
+	for _, id := range ids {
+		found := false
+		for _, op := range ops {
+			if op.ID == id {
+				selected = append(selected, op)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no queued operation with id '%s'", id)
+		}
+	}
+
+	return selected, nil
+}
+
+// completeOperationIDs completes the IDs of queued operations
+func completeOperationIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ops, err := internalOutbox.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ids := make([]string, 0, len(ops))
+	for _, op := range ops {
+		ids = append(ids, op.ID+"\t"+op.Describe())
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -1,7 +1,9 @@
 package sprint
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/hyphaene/hexa/internal/cache"
 	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -17,9 +20,9 @@ var (
 	filterFlag       string
 	noCacheFlag      bool
 	jsonFlag         bool
-	verboseFlag      bool
 	sprintNumberFlag int
 	outputFlag       string
+	atFlag           string
 )
 
 var fetchCmd = &cobra.Command{
@@ -40,7 +43,15 @@ Filter options:
 
 Cache behavior:
   By default, ticket data is cached for 5 minutes.
-  Use --no-cache to force a fresh fetch from Jira API.`,
+  An expired cache is refreshed incrementally: only tickets updated since
+  the last fetch are downloaded (see jira.incremental in config).
+  Use --no-cache to force a full fetch from Jira API.
+
+History replay:
+  Every fetch from Jira API is recorded in a local snapshot history.
+  Use --at to replay the sprint as it was at a given time, without any API call:
+  the sprint active at that time, or the one of --sprint-number (fetched at least once).
+    hexa jira sprint fetch --at 2026-10-10T09:00`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFetch,
 }
@@ -50,9 +61,9 @@ func init() {
 	fetchCmd.Flags().StringVar(&filterFlag, "filter", "all", "Filter by assignee: me|unassigned|all")
 	fetchCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Bypass cache and fetch fresh data")
 	fetchCmd.Flags().BoolVar(&jsonFlag, "json", false, "Output results in JSON format")
-	fetchCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed progress information")
 	fetchCmd.Flags().IntVar(&sprintNumberFlag, "sprint-number", 0, "Fetch specific sprint by number (e.g., 35)")
 	fetchCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write output to file (markdown by default, JSON if --json)")
+	fetchCmd.Flags().StringVar(&atFlag, "at", "", "Replay the sprint from the snapshot history at a given time (e.g., 2026-10-10T09:00)")
 
 	// Register completion for filter flag values
 	_ = fetchCmd.RegisterFlagCompletionFunc("filter", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -66,14 +77,12 @@ func init() {
 }
 
 func runFetch(cmd *cobra.Command, args []string) error {
-	// Verbose logging
-	if verboseFlag && !jsonFlag {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Starting fetch command\n")
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Jira URL: %s\n", viper.GetString("jira.url"))
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Board ID: %d\n", viper.GetInt("jira.boardId"))
-		if viper.GetString("jira.token") == "" {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  [WARN] jira.token is not configured!\n")
-		}
+	if jsonFlag {
+		logger.DisableProgress() // Keep JSON output free of terminal noise
+	}
+	logger.Debug("Starting fetch command", "jiraURL", viper.GetString("jira.url"), "boardId", viper.GetInt("jira.boardId"))
+	if !jira.HasCredentials() {
+		logger.Warn("jira.token is not configured (or no OAuth session, see 'hexa jira login')!")
 	}
 
 	var statusName string
@@ -96,161 +105,224 @@ func runFetch(cmd *cobra.Command, args []string) error {
 			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "\nUsage: hexa jira sprint fetch [status] [--filter=<me|unassigned|all>] [--no-cache]\n")
 			return fmt.Errorf("invalid status key")
 		}
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Status mapped: %s -> %s\n", statusKey, statusName)
+		logger.Debug("Status mapped", "key", statusKey, "status", statusName)
+	}
+
+	var sprintID int
+	var tickets []jira.Ticket
+	var total int
+	var cacheAge time.Duration
+	var err error
+
+	// Replay from snapshot history, or load from cache/API
+	if atFlag != "" {
+		sprintID, tickets, total, cacheAge, err = loadSnapshotTickets(cmd)
+	} else {
+		sprintID, tickets, total, cacheAge, err = loadSprintTickets(cmd)
+	}
+	if err != nil {
+		return err
+	}
+	if jira.IsOffline() && atFlag == "" {
+		printOfflineBanner(cmd, cacheAge)
+	}
+
+	// Filter by status (only if status arg provided)
+	if filterByStatus {
+		tickets = jira.FilterByStatus(tickets, statusName)
+	}
+
+	// Filter by assignee
+	switch filterFlag {
+	case "me":
+		if jira.ConfiguredUser().IsZero() && atFlag != "" {
+			return fmt.Errorf("jira.userEmail or jira.accountId must be configured to use --filter=me with --at")
+		}
+		me, err := resolveMe(cmd, jsonFlag)
+		if err != nil {
+			return err
 		}
+
+		tickets = jira.FilterByAssignee(tickets, "me", me)
+	case "unassigned":
+		tickets = jira.FilterByAssignee(tickets, "unassigned", jira.UserProfile{})
+	}
+
+	// Display output
+	displayStatus := statusName
+	if !filterByStatus {
+		displayStatus = "tous statuts"
+	}
+
+	// Handle output flag
+	if outputFlag != "" {
+		return writeToFile(outputFlag, tickets, cacheAge, total, displayStatus, filterFlag, noCacheFlag, sprintID, jsonFlag)
+	}
+
+	if jsonFlag {
+		return outputJSON(cmd, tickets, cacheAge, total, displayStatus, filterFlag, noCacheFlag, sprintID)
 	}
 
+	formatOutput(cmd, tickets, cacheAge, total, displayStatus, filterFlag, noCacheFlag)
+
+	return nil
+}
+
+// loadSprintTickets resolves the sprint ID and returns its tickets from cache or Jira API
+func loadSprintTickets(cmd *cobra.Command) (int, []jira.Ticket, int, time.Duration, error) {
 	// Get sprint ID (current or specific number)
 	var sprintID int
+	var tickets []jira.Ticket
+	var total int
+	var cacheAge time.Duration
+
 	if sprintNumberFlag > 0 {
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Resolving sprint number %d...\n", sprintNumberFlag)
-		}
+		logger.Debug("Resolving sprint number", "number", sprintNumberFlag)
 		var err error
-		sprintID, err = jira.GetSprintIdFromNumber(sprintNumberFlag)
+		sprintID, err = jira.GetSprintIdFromNumber(cmd.Context(), sprintNumberFlag)
 		if err != nil {
-			return fmt.Errorf("resolving sprint number %d: %w", sprintNumberFlag, err)
+			return 0, nil, 0, 0, fmt.Errorf("resolving sprint number %d: %w", sprintNumberFlag, err)
 		}
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Sprint ID: %d\n", sprintID)
+		logger.Debug("Sprint resolved", "sprintId", sprintID)
+		if err := cache.RecordSprintNumber(sprintNumberFlag, sprintID); err != nil {
+			logger.Debug("Recording sprint number", "error", err)
 		}
 	} else {
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Fetching current sprint ID...\n")
-		}
+		logger.Debug("Fetching current sprint ID")
 		var err error
-		sprintID, err = jira.GetCurrentSprintId()
+		sprintID, err = cache.CurrentSprintID(cmd.Context())
 		if err != nil {
-			return fmt.Errorf("getting current sprint ID: %w", err)
+			return 0, nil, 0, 0, fmt.Errorf("getting current sprint ID: %w", err)
 		}
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Sprint ID: %d\n", sprintID)
+		logger.Debug("Sprint resolved", "sprintId", sprintID)
+		if err := cache.RecordActiveSprint(sprintID, time.Now()); err != nil {
+			logger.Debug("Recording active sprint", "error", err)
 		}
 	}
 
 	// Check cache
-	if verboseFlag && !jsonFlag {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Checking cache for sprint %d...\n", sprintID)
-	}
+	logger.Debug("Checking cache", "sprintId", sprintID)
 	cachedEntry, err := cache.ReadCache(sprintID)
 	if err != nil {
-		if !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  Cache file corrupted, refreshing...\n")
-		}
+		logger.Warn("Cache file corrupted, refreshing...", "error", err)
 		cachedEntry = nil // Treat corrupted cache as cache miss
-	} else if cachedEntry != nil && verboseFlag && !jsonFlag {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Cache found (age: %s)\n", formatDuration(cachedEntry.Age()))
+	} else if cachedEntry != nil {
+		logger.Debug("Cache found", "age", formatDuration(cachedEntry.Age()))
 	}
 
-	var tickets []jira.Ticket
-	var total int
-	var cacheAge time.Duration
-
 	// Determine if we need to refresh
 	if cache.ShouldRefresh(cachedEntry, noCacheFlag) {
 		if !jsonFlag {
 			if noCacheFlag {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Récupération complète des tickets du sprint...\n")
 			} else if cachedEntry == nil {
-				if verboseFlag {
-					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] No cache found, fetching from API...\n")
-				}
+				logger.Debug("No cache found, fetching from API")
+			} else if cache.IncrementalEnabled() {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Mise à jour incrémentale des tickets du sprint...\n")
 			} else {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Récupération complète des tickets du sprint...\n")
 			}
 		}
 
-		// Fetch from API
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Calling Jira API /rest/agile/1.0/sprint/%d/issue...\n", sprintID)
+		// Fetch from API: only the tickets updated since the cached entry, unless --no-cache
+		logger.Debug("Calling Jira API", "sprintId", sprintID, "incremental", !noCacheFlag && cachedEntry != nil && cache.IncrementalEnabled())
+		previous := cachedEntry
+		if noCacheFlag {
+			previous = nil
 		}
-		fetchedTickets, fetchedTotal, err := jira.FetchSprintTickets(sprintID)
-		if err != nil {
-			return handleAPIError(err, cmd)
+		entry, err := cache.RefreshSprint(cmd.Context(), sprintID, refreshNotBefore(cachedEntry, noCacheFlag), cache.IncrementalFetcher(previous, func(ctx context.Context) ([]jira.Ticket, int, error) {
+			return jira.FetchSprintTickets(ctx, sprintID)
+		}))
+		if entry == nil && jira.IsConnectionError(err) && cachedEntry != nil {
+			// Jira unreachable: fall back to the (possibly expired) cached tickets
+			return sprintID, cachedEntry.Issues, cachedEntry.Total, cachedEntry.Age(), nil
+		}
+		if entry == nil && errors.Is(err, jira.ErrOffline) {
+			return 0, nil, 0, 0, fmt.Errorf("no cached tickets for sprint %d: %w", sprintID, cache.ErrNoOfflineData)
 		}
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Received %d tickets from API\n", fetchedTotal)
+		if entry == nil {
+			return 0, nil, 0, 0, handleAPIError(err, cmd)
 		}
+		logger.Debug("Received tickets from API", "total", entry.Total)
 
-		// Write to cache
-		if err := cache.WriteCache(sprintID, fetchedTickets, fetchedTotal); err != nil {
-			// Non-fatal: log warning but continue
-			if !jsonFlag {
-				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to write cache: %v\n", err)
-			}
-		} else if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Cache written successfully\n")
+		// Cache write failure is non-fatal: log warning but continue
+		if err != nil {
+			logger.Warn("failed to write cache", "error", err)
+		} else {
+			logger.Debug("Cache written successfully")
 		}
 
-		tickets = fetchedTickets
-		total = fetchedTotal
-		cacheAge = 0
+		tickets = entry.Issues
+		total = entry.Total
+		cacheAge = entry.Age()
 	} else {
 		// Use cached data
-		if verboseFlag && !jsonFlag {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "🔍 [DEBUG] Using cached data\n")
-		}
+		logger.Debug("Using cached data")
 		tickets = cachedEntry.Issues
 		total = cachedEntry.Total
 		cacheAge = cachedEntry.Age()
 	}
 
-	// Filter by status (only if status arg provided)
-	if filterByStatus {
-		tickets = jira.FilterByStatus(tickets, statusName)
-	}
-
-	// Filter by assignee
-	switch filterFlag {
-	case "me":
-		userEmail := viper.GetString("jira.userEmail")
-		if userEmail == "" {
-			// Fetch user profile
-			if !jsonFlag {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Fetching user profile from Jira API...\n")
-			}
-			profile, err := jira.FetchCurrentUser()
-			if err != nil {
-				return fmt.Errorf("fetching user profile: %w", err)
-			}
+	return sprintID, tickets, total, cacheAge, nil
+}
 
-			userEmail = profile.EmailAddress
+// refreshNotBefore returns the time after which a refresh made meanwhile by another process can be reused
+func refreshNotBefore(cachedEntry *cache.CacheEntry, noCache bool) time.Time {
+	if noCache {
+		return time.Now()
+	}
+	if cachedEntry != nil {
+		return cachedEntry.CachedAt
+	}
+	return time.Time{} // Any entry written since our cache miss is fresh
+}
 
-			// Save to config
-			if err := jira.SaveUserEmail(userEmail); err != nil {
-				// Non-fatal: log warning
-				if !jsonFlag {
-					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to save user email to config: %v\n", err)
-				}
-			} else if !jsonFlag {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ User email saved to config: %s\n", userEmail)
-			}
-		}
+// loadSnapshotTickets returns the tickets of the latest snapshot taken at or before --at
+func loadSnapshotTickets(cmd *cobra.Command) (int, []jira.Ticket, int, time.Duration, error) {
+	at, err := parseAtTime(atFlag)
+	if err != nil {
+		return 0, nil, 0, 0, err
+	}
 
-		tickets = jira.FilterByAssignee(tickets, "me", userEmail)
-	case "unassigned":
-		tickets = jira.FilterByAssignee(tickets, "unassigned", "")
+	// The sprint is resolved from what previous fetches recorded, never through the API:
+	// --sprint-number, or the sprint that was active at that time
+	var sprintID int
+	if sprintNumberFlag > 0 {
+		sprintID, err = cache.RecordedSprintNumber(sprintNumberFlag)
+	} else {
+		sprintID, err = cache.ActiveSprintAt(at)
+	}
+	if err != nil {
+		return 0, nil, 0, 0, fmt.Errorf("reading snapshot history: %w", err)
 	}
 
-	// Display output
-	displayStatus := statusName
-	if !filterByStatus {
-		displayStatus = "tous statuts"
+	snapshot, err := cache.ReadSnapshotAt(sprintID, at)
+	if err != nil {
+		return 0, nil, 0, 0, fmt.Errorf("reading snapshot history: %w", err)
 	}
 
-	// Handle output flag
-	if outputFlag != "" {
-		return writeToFile(outputFlag, tickets, cacheAge, total, displayStatus, filterFlag, noCacheFlag, sprintID, jsonFlag)
+	if !jsonFlag {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🕰️  Snapshot du sprint %d pris le %s\n",
+			snapshot.SprintID, snapshot.CachedAt.Local().Format("2006-01-02 15:04:05"))
 	}
 
-	if jsonFlag {
-		return outputJSON(cmd, tickets, cacheAge, total, displayStatus, filterFlag, noCacheFlag, sprintID)
+	return snapshot.SprintID, snapshot.Issues, snapshot.Total, snapshot.Age(), nil
+}
+
+// parseAtTime parses the --at value, interpreted in local time unless a zone is given
+func parseAtTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
 
-	formatOutput(cmd, tickets, cacheAge, total, displayStatus, filterFlag, noCacheFlag)
+	layouts := []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
 
-	return nil
+	return time.Time{}, fmt.Errorf("invalid --at value '%s', expected e.g. 2026-10-10T09:00", value)
 }
 
 func formatOutput(cmd *cobra.Command, tickets []jira.Ticket, cacheAge time.Duration, total int, statusName string, filter string, noCache bool) {
@@ -312,6 +384,11 @@ func handleAPIError(err error, cmd *cobra.Command) error {
 
 	if strings.Contains(errMsg, "status 401") {
 		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: Jira API authentication failed (401 Unauthorized)\n\n")
+		if jira.AuthType() == jira.AuthOAuth {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Your OAuth session may have been revoked, log in again:\n")
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  hexa jira login\n")
+			return fmt.Errorf("authentication failed")
+		}
 		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Please verify your Jira token:\n")
 		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  hexa config local get jira.token\n\n")
 		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "To update your token:\n")
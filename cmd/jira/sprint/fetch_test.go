@@ -0,0 +1,153 @@
+package sprint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// newFakeJira starts a fake Jira with an active sprint: PROJ-1 to do and assigned to the
+// authenticated user, PROJ-2 in progress and unassigned, PROJ-3 to do and assigned to someone else
+func newFakeJira(t *testing.T) *jiratest.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	jira.SetOffline(false)
+
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+	server.Configure()
+	viper.Set("jira.boardId", 3)
+	t.Cleanup(func() {
+		for _, key := range []string{"jira.url", "jira.token", "jira.boardId", "jira.userEmail", "jira.accountId"} {
+			viper.Set(key, nil)
+		}
+	})
+
+	server.AddBoard(3, "Team board")
+	server.AddSprint(3, jira.Sprint{ID: 42, Name: "Sprint 12", State: "active"})
+	me := &jira.Assignee{AccountID: "test-account", Name: "jdoe", DisplayName: "John Doe"}
+	other := &jira.Assignee{AccountID: "other-account", Name: "asmith", DisplayName: "Alice Smith"}
+	server.AddIssue(42, jira.Ticket{Key: "PROJ-1", Fields: jira.Fields{Summary: "Mine", Status: jira.Status{Name: "To Do"}, Assignee: me}})
+	server.AddIssue(42, jira.Ticket{Key: "PROJ-2", Fields: jira.Fields{Summary: "Nobody's", Status: jira.Status{Name: "In Progress"}}})
+	server.AddIssue(42, jira.Ticket{Key: "PROJ-3", Fields: jira.Fields{Summary: "Alice's", Status: jira.Status{Name: "To Do"}, Assignee: other}})
+	return server
+}
+
+// runSprint executes hexa jira sprint with args, flags reset to their defaults, and returns its output
+func runSprint(t *testing.T, args ...string) string {
+	t.Helper()
+	fetchCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	var out bytes.Buffer
+	SprintCmd.SetOut(&out)
+	SprintCmd.SetErr(&out)
+	SprintCmd.SetArgs(args)
+	t.Cleanup(func() {
+		SprintCmd.SetOut(nil)
+		SprintCmd.SetErr(nil)
+		SprintCmd.SetArgs(nil)
+	})
+	if err := SprintCmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("hexa jira sprint %s: %v\n%s", strings.Join(args, " "), err, out.String())
+	}
+	return out.String()
+}
+
+// fetchJSON runs hexa jira sprint fetch --json with args and decodes its output
+func fetchJSON(t *testing.T, args ...string) JSONOutput {
+	t.Helper()
+	out := runSprint(t, append([]string{"fetch", "--json"}, args...)...)
+	var output JSONOutput
+	if err := json.Unmarshal([]byte(out), &output); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, out)
+	}
+	return output
+}
+
+func keysOf(tickets []jira.Ticket) string {
+	keys := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		keys[i] = ticket.Key
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     string // Ticket keys, in order
+		wantUser bool   // The authenticated user is fetched and remembered
+	}{
+		{name: "all tickets", want: "PROJ-1,PROJ-2,PROJ-3"},
+		{name: "status", args: []string{"to-do"}, want: "PROJ-1,PROJ-3"},
+		{name: "me", args: []string{"--filter=me"}, want: "PROJ-1", wantUser: true},
+		{name: "unassigned", args: []string{"--filter=unassigned"}, want: "PROJ-2"},
+		{name: "status and me", args: []string{"to-do", "--filter=me"}, want: "PROJ-1", wantUser: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newFakeJira(t)
+
+			output := fetchJSON(t, tt.args...)
+			if got := keysOf(output.Tickets); got != tt.want {
+				t.Errorf("tickets = %s, want %s", got, tt.want)
+			}
+			if output.Sprint.ID != 42 || output.Sprint.Total != 3 || output.Summary.Count != len(output.Tickets) {
+				t.Errorf("sprint = %+v, summary = %+v", output.Sprint, output.Summary)
+			}
+			if got := viper.GetString("jira.accountId"); (got == "test-account") != tt.wantUser {
+				t.Errorf("jira.accountId = %q, want remembered %v", got, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestFetchUsesCache(t *testing.T) {
+	server := newFakeJira(t)
+
+	fetchJSON(t)
+	server.UpdateIssue("PROJ-2", func(ticket *jira.Ticket) { ticket.Fields.Status = jira.Status{Name: "Done"} })
+	requests := len(server.Requests())
+
+	// Within the TTL, the second fetch reads the cache without asking Jira for the sprint
+	output := fetchJSON(t, "in-progress")
+	if got := keysOf(output.Tickets); got != "PROJ-2" {
+		t.Errorf("cached tickets = %s, want PROJ-2", got)
+	}
+	for _, request := range server.Requests()[requests:] {
+		if strings.Contains(request, "/issue") {
+			t.Errorf("tickets requested despite the cache: %s", request)
+		}
+	}
+
+	// --no-cache sees the update
+	if output := fetchJSON(t, "in-progress", "--no-cache"); len(output.Tickets) != 0 {
+		t.Errorf("tickets after --no-cache = %s, want none", keysOf(output.Tickets))
+	}
+}
+
+func TestFetchMarkdownOutput(t *testing.T) {
+	newFakeJira(t)
+
+	out := runSprint(t, "fetch", "--filter=unassigned")
+	for _, want := range []string{"PROJ-2", "Nobody's"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output misses %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "PROJ-1") {
+		t.Errorf("assigned ticket listed with --filter=unassigned:\n%s", out)
+	}
+}
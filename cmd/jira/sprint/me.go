@@ -0,0 +1,46 @@
+package sprint
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/logger"
+)
+
+// resolveMe returns the identity used by "me" filters: jira.userEmail/jira.accountId from config,
+// or the Jira profile (cached), saved to config for next time. On Jira Cloud, a configured email
+// alone is completed with the account ID. Progress is printed unless quiet.
+func resolveMe(cmd *cobra.Command, quiet bool) (jira.UserProfile, error) {
+	configured := jira.ConfiguredUser()
+	if configured.IsComplete() {
+		return configured, nil
+	}
+
+	if !quiet {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Fetching user profile from Jira API...\n")
+	}
+	profile, err := cache.CurrentUser(cmd.Context())
+	if err != nil {
+		return jira.UserProfile{}, fmt.Errorf("fetching user profile: %w", err)
+	}
+
+	me := *profile
+	if me.EmailAddress == "" {
+		me.EmailAddress = configured.EmailAddress // Hidden by Jira Cloud privacy settings
+	}
+
+	// Save to config
+	if err := jira.SaveUser(&me); err != nil {
+		// Non-fatal: log warning
+		logger.Warn("failed to save user to config", "error", err)
+	} else if !quiet && configured.EmailAddress == "" && me.EmailAddress != "" {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ User email saved to config: %s\n", me.EmailAddress)
+	} else if !quiet {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ User account ID saved to config: %s\n", me.AccountID)
+	}
+
+	return me, nil
+}
@@ -1,12 +1,15 @@
 package sprint
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hyphaene/hexa/internal/cache"
 	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/logger"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var pulseCmd = &cobra.Command{
@@ -30,10 +33,13 @@ func init() {
 
 func runPulse(cmd *cobra.Command, args []string) error {
 	// Get current sprint ID
-	sprintID, err := jira.GetCurrentSprintId()
+	sprintID, err := cache.CurrentSprintID(cmd.Context())
 	if err != nil {
 		return fmt.Errorf("getting current sprint ID: %w", err)
 	}
+	if err := cache.RecordActiveSprint(sprintID, time.Now()); err != nil {
+		logger.Debug("Recording active sprint", "error", err)
+	}
 
 	// Check cache
 	cachedEntry, err := cache.ReadCache(sprintID)
@@ -46,21 +52,33 @@ func runPulse(cmd *cobra.Command, args []string) error {
 
 	// Determine if we need to refresh
 	if cache.ShouldRefresh(cachedEntry, false) {
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Récupération complète des tickets du sprint...\n")
+		if cachedEntry != nil && cache.IncrementalEnabled() {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Mise à jour incrémentale des tickets du sprint...\n")
+		} else {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Récupération complète des tickets du sprint...\n")
+		}
 
 		// Fetch from API
-		fetchedTickets, fetchedTotal, err := jira.FetchSprintTickets(sprintID)
-		if err != nil {
+		entry, err := cache.RefreshSprint(cmd.Context(), sprintID, refreshNotBefore(cachedEntry, false), cache.IncrementalFetcher(cachedEntry, func(ctx context.Context) ([]jira.Ticket, int, error) {
+			return jira.FetchSprintTickets(ctx, sprintID)
+		}))
+		switch {
+		case entry == nil && jira.IsConnectionError(err) && cachedEntry != nil:
+			// Jira unreachable: fall back to the (possibly expired) cached tickets
+			tickets = cachedEntry.Issues
+			total = cachedEntry.Total
+		case entry == nil && errors.Is(err, jira.ErrOffline):
+			return fmt.Errorf("no cached tickets for sprint %d: %w", sprintID, cache.ErrNoOfflineData)
+		case entry == nil:
 			return fmt.Errorf("fetching sprint tickets: %w", err)
-		}
+		default:
+			if err != nil {
+				logger.Warn("failed to write cache", "error", err)
+			}
 
-		// Write to cache
-		if err := cache.WriteCache(sprintID, fetchedTickets, fetchedTotal); err != nil {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to write cache: %v\n", err)
+			tickets = entry.Issues
+			total = entry.Total
 		}
-
-		tickets = fetchedTickets
-		total = fetchedTotal
 	} else {
 		// Use cached data
 		tickets = cachedEntry.Issues
@@ -69,29 +87,22 @@ func runPulse(cmd *cobra.Command, args []string) error {
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "📋 Utilisation du cache (âge: %s)\n", formatDuration(cacheAge))
 	}
 
-	// Get user email for "me" filters
-	userEmail := viper.GetString("jira.userEmail")
-	if userEmail == "" {
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🔄 Fetching user profile from Jira API...\n")
-		profile, err := jira.FetchCurrentUser()
-		if err != nil {
-			return fmt.Errorf("fetching user profile: %w", err)
-		}
-
-		userEmail = profile.EmailAddress
+	if jira.IsOffline() && cachedEntry != nil {
+		printOfflineBanner(cmd, cachedEntry.Age())
+	}
 
-		// Save to config
-		if err := jira.SaveUserEmail(userEmail); err != nil {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to save user email to config: %v\n", err)
-		}
+	// Get user identity for "me" filters
+	me, err := resolveMe(cmd, false)
+	if err != nil {
+		return err
 	}
 
 	// Filter by status and assignee in-memory
 	myTodo := jira.FilterByStatus(tickets, "To Do")
-	myTodo = jira.FilterByAssignee(myTodo, "me", userEmail)
+	myTodo = jira.FilterByAssignee(myTodo, "me", me)
 
 	myInProgress := jira.FilterByStatus(tickets, "In Progress")
-	myInProgress = jira.FilterByAssignee(myInProgress, "me", userEmail)
+	myInProgress = jira.FilterByAssignee(myInProgress, "me", me)
 
 	allDeployUat := jira.FilterByStatus(tickets, "DEPLOY IN UAT")
 
@@ -138,3 +149,8 @@ func printTicketList(cmd *cobra.Command, tickets []jira.Ticket) {
 			ticket.Key, ticket.Fields.Summary, assignee, priority)
 	}
 }
+
+// printOfflineBanner warns that the displayed data comes from the local cache
+func printOfflineBanner(cmd *cobra.Command, age time.Duration) {
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "📴 ═══ MODE HORS LIGNE ═══ Données servies depuis le cache local (âge: %s), elles peuvent être périmées.\n", formatDuration(age))
+}
@@ -0,0 +1,46 @@
+package ticket
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/outbox"
+)
+
+func init() {
+	TicketCmd.AddCommand(assignTicketCmd)
+}
+
+var assignTicketCmd = &cobra.Command{
+	Use:   "assign <ticket> <username|accountId|me>",
+	Short: "Assign a Jira ticket",
+	Long: `Assign a Jira ticket to a user.
+
+If Jira is unreachable, the assignment is queued in the outbox (see 'hexa jira outbox').
+
+Example:
+  hexa jira ticket assign PROJ-123 me`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		assignee := args[1]
+		if assignee == "me" {
+			profile, err := cache.CurrentUser(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("fetching user profile: %w", err)
+			}
+			assignee = profile.Name
+			if jira.IsCloud() {
+				assignee = profile.AccountID // Jira Cloud has no usernames
+			}
+		}
+
+		return submitOperation(cmd, &outbox.Operation{
+			Type:     outbox.OpAssign,
+			IssueKey: args[0],
+			Assignee: assignee,
+		})
+	},
+}
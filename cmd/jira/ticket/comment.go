@@ -1,9 +1,11 @@
 package ticket
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/hyphaene/hexa/internal/outbox"
 )
 
 func init() {
@@ -11,11 +13,20 @@ func init() {
 }
 
 var commentTicketCmd = &cobra.Command{
-	Use:   "comment",
+	Use:   "comment <ticket> <text>",
 	Short: "Add a comment to a Jira ticket",
-	Long:  `Add a comment to a Jira ticket.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Adding comment to Jira ticket...")
-		// Add comment logic here
+	Long: `Add a comment to a Jira ticket.
+
+If Jira is unreachable, the comment is queued in the outbox (see 'hexa jira outbox').
+
+Example:
+  hexa jira ticket comment PROJ-123 "Work in progress"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return submitOperation(cmd, &outbox.Operation{
+			Type:     outbox.OpComment,
+			IssueKey: args[0],
+			Body:     strings.Join(args[1:], " "),
+		})
 	},
 }
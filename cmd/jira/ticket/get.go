@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/hyphaene/hexa/internal/cache"
 	"github.com/hyphaene/hexa/internal/jira"
 )
 
@@ -13,16 +14,47 @@ func init() {
 }
 
 var getTicketCmd = &cobra.Command{
-	Use:   "get",
+	Use:   "get [ticket]",
 	Short: "Get details of a Jira ticket",
-	Long:  `Fetch and display details of a specific Jira ticket.`,
+	Long: `Fetch and display details of a specific Jira ticket.
+
+Without a ticket key, displays the current sprint ID.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		sprintId, err := jira.GetCurrentSprintId()
+		if len(args) == 1 {
+			ticket, err := cache.Issue(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Println("Error fetching ticket:", err)
+				return
+			}
+			if jira.IsOffline() {
+				fmt.Println("📴 Mode hors ligne: ticket servi depuis le cache local")
+			}
+
+			assignee := "Non assigné"
+			if ticket.Fields.Assignee != nil {
+				assignee = ticket.Fields.Assignee.DisplayName
+			}
+			priority := "Medium"
+			if ticket.Fields.Priority != nil {
+				priority = ticket.Fields.Priority.Name
+			}
+
+			fmt.Printf("%s - %s\n", ticket.Key, ticket.Fields.Summary)
+			fmt.Printf("  Status:   %s\n", ticket.Fields.Status.Name)
+			fmt.Printf("  Assignee: %s\n", assignee)
+			fmt.Printf("  Priority: %s\n", priority)
+			return
+		}
+
+		sprintId, err := cache.CurrentSprintID(cmd.Context())
 		if err != nil {
 			fmt.Println("Error fetching current sprint ID:", err)
 			return
 		}
+		if jira.IsOffline() {
+			fmt.Println("📴 Mode hors ligne: dernier sprint connu (cache local)")
+		}
 		fmt.Println("Current Sprint ID:", sprintId)
-		// Add logic to fetch and display ticket details here
 	},
 }
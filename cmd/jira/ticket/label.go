@@ -0,0 +1,30 @@
+package ticket
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyphaene/hexa/internal/outbox"
+)
+
+func init() {
+	TicketCmd.AddCommand(labelTicketCmd)
+}
+
+var labelTicketCmd = &cobra.Command{
+	Use:   "label <ticket> <label>...",
+	Short: "Add labels to a Jira ticket",
+	Long: `Add one or more labels to a Jira ticket, keeping the existing ones.
+
+If Jira is unreachable, the labels are queued in the outbox (see 'hexa jira outbox').
+
+Example:
+  hexa jira ticket label PROJ-123 backend needs-review`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return submitOperation(cmd, &outbox.Operation{
+			Type:     outbox.OpLabel,
+			IssueKey: args[0],
+			Labels:   args[1:],
+		})
+	},
+}
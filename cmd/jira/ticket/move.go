@@ -2,20 +2,67 @@ package ticket
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/outbox"
 )
 
+var moveStatusFlag string
+
 func init() {
 	TicketCmd.AddCommand(moveTicketCmd)
+	moveTicketCmd.Flags().StringVar(&moveStatusFlag, "status", "", "Target status (status key like in-progress, or Jira status name)")
+
+	_ = moveTicketCmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return jira.ValidStatusKeys(), cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 var moveTicketCmd = &cobra.Command{
-	Use:   "move",
+	Use:   "move <ticket> [status]",
 	Short: "Move a Jira ticket",
-	Long:  `Move a Jira ticket to a different status.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Moving Jira ticket...")
-		// Add move logic here
+	Long: `Move a Jira ticket to a different status.
+
+The status can be a CLI status key (in-progress, to-test...) or a Jira status name.
+If Jira is unreachable, the move is queued in the outbox (see 'hexa jira outbox').
+
+Example:
+  hexa jira ticket move PROJ-123 --status in-progress`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status := moveStatusFlag
+		if len(args) == 2 {
+			status = args[1]
+		}
+		if status == "" {
+			return fmt.Errorf("target status is required (--status)")
+		}
+
+		// Accept CLI status keys as well as raw Jira status names
+		if statusName, err := jira.MapStatusKey(status); err == nil {
+			status = statusName
+		} else if statuses, err := cache.Statuses(cmd.Context()); err == nil && !hasStatus(statuses, status) {
+			return fmt.Errorf("unknown status '%s' (status keys: %v)", status, jira.ValidStatusKeys())
+		}
+
+		return submitOperation(cmd, &outbox.Operation{
+			Type:     outbox.OpTransition,
+			IssueKey: args[0],
+			Status:   status,
+		})
 	},
 }
+
+// hasStatus reports whether name is one of the Jira statuses, case-insensitively
+func hasStatus(statuses []jira.Status, name string) bool {
+	for _, status := range statuses {
+		if strings.EqualFold(status.Name, name) {
+			return true
+		}
+	}
+	return false
+}
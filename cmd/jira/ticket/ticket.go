@@ -1,9 +1,18 @@
 package ticket
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/hyphaene/hexa/cmd/jira"
+	"github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/outbox"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func init() {
@@ -17,3 +26,49 @@ var (
 		Long:  `Commands to interact with Jira tickets.`,
 	}
 )
+
+// submitOperation sends a write operation to Jira, queuing it in the outbox when Jira is unreachable
+func submitOperation(cmd *cobra.Command, op *outbox.Operation) error {
+	// Remember the status the ticket had, to detect conflicts if the operation is replayed later
+	// (the cache may be stale: replay only reports changes made after the operation was queued)
+	if ticket, err := cache.FindTicket(op.IssueKey); err == nil && ticket != nil {
+		op.BaseStatus = ticket.Fields.Status.Name
+	}
+
+	queued, err := outbox.Submit(cmd.Context(), op)
+	if errors.Is(err, outbox.ErrUncertain) {
+		queued, err = queueUncertain(cmd, op, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op.Describe(), err)
+	}
+
+	if queued {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "📮 Jira injoignable, opération mise en file d'attente: %s\n", op.Describe())
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "   Envoyez-la plus tard avec: hexa jira outbox replay\n")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ %s\n", op.Describe())
+	return nil
+}
+
+// queueUncertain asks whether to queue an operation Jira may already have applied: replaying it
+// could post a comment twice, so it is only queued when the user says so
+func queueUncertain(cmd *cobra.Command, op *outbox.Operation, cause error) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("%w (check the ticket before retrying)", cause)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  Jira n'a pas répondu, l'opération a peut-être été appliquée: %s\n", op.Describe())
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), "   Vérifiez le ticket. La mettre en file d'attente quand même ? (o/N): ")
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "o", "oui", "y", "yes":
+		if err := outbox.Save(op); err != nil {
+			return false, fmt.Errorf("queuing operation: %w", err)
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("%w (check the ticket before retrying)", cause)
+}
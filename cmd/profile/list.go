@@ -0,0 +1,43 @@
+package profile
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the defined profiles",
+	Long: `List the profiles defined under profiles in the configuration files, with their
+Jira URL and board. The active profile is marked.
+
+Example:
+  hexa profile list`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := config.Profiles()
+		if len(names) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ℹ️  Aucun profil défini (section %s de la configuration)\n", config.ProfilesKey)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, name := range names {
+			url := config.ProfileValue(name, "jira.url")
+			board := config.ProfileValue(name, "jira.boardId")
+			marker := "" // Trailing: emojis would break the tabwriter alignment
+			if name == config.ActiveProfile() {
+				marker = "✅"
+			}
+			_, _ = fmt.Fprintf(w, "   %s\t%s\t%s\t%s\n", name, config.FormatValue(url), config.FormatValue(board), marker)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	ProfileCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,36 @@
+package profile
+
+import (
+	"github.com/hyphaene/hexa/cmd"
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.RootCmd.AddCommand(ProfileCmd)
+}
+
+var ProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named profiles (one per Jira instance or board)",
+	Long: `Profiles group the settings of a Jira instance or board under profiles.<name>:
+
+  profiles:
+    client-a:
+      jira:
+        url: https://jira.client-a.com
+        token: secret://jira/client-a
+        boardId: 12
+
+The active profile (--profile, ` + config.ProfileEnv + `, or the profile key set by
+hexa profile use) is applied over the configuration files. Each profile has its own
+cache, outbox and OAuth tokens under ~/.hexa/profiles/<name>.`,
+}
+
+// completeProfiles completes the first argument with the defined profiles
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.Profiles(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var showSecrets bool
+
+var showCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show the settings of a profile",
+	Long: `Show the settings of a profile (the active one by default) and where its cache and
+outbox are stored. Secrets are masked unless --show-secrets is given.
+
+Example:
+  hexa profile show client-a`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProfiles,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		name := config.ActiveProfile()
+		if len(args) > 0 {
+			name = strings.ToLower(args[0])
+		}
+		if name == "" {
+			_, _ = fmt.Fprintf(out, "ℹ️  Aucun profil actif (hexa profile use <name>, --profile ou %s)\n", config.ProfileEnv)
+			return nil
+		}
+
+		settings := config.ProfileSettings(name)
+		if settings == nil {
+			return fmt.Errorf("profile '%s' is not defined (hexa profile list)", name)
+		}
+		if !showSecrets {
+			settings = config.MaskSettings(settings)
+		}
+		stateDir, err := config.ProfileStateDir(name)
+		if err != nil {
+			return err
+		}
+
+		status := ""
+		if name == config.ActiveProfile() {
+			status = " (actif)"
+		}
+		_, _ = fmt.Fprintf(out, "👤 Profil: %s%s\n", name, status)
+		_, _ = fmt.Fprintf(out, "   Cache et outbox: %s\n", stateDir)
+		_, _ = fmt.Fprintln(out, "---")
+
+		data, err := yaml.Marshal(settings)
+		if err != nil {
+			return fmt.Errorf("encoding profile: %w", err)
+		}
+		_, _ = fmt.Fprint(out, string(data))
+		return nil
+	},
+}
+
+func init() {
+	showCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret values (tokens, passwords...) instead of masking them")
+	ProfileCmd.AddCommand(showCmd)
+}
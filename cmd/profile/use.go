@@ -0,0 +1,65 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var useLocal bool
+
+var useCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Select the active profile",
+	Long: `Select the profile applied by every command, saved as the profile key of ~/.hexa.yml
+(or of .hexa.local.yml with --local, for the current project only).
+"` + config.DefaultProfile + `" goes back to the configuration without profile.
+--profile and ` + config.ProfileEnv + ` still override the saved profile.
+
+Example:
+  hexa profile use client-a
+  hexa profile use ` + config.DefaultProfile + ` --local`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfiles,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.ToLower(args[0])
+		if name != config.DefaultProfile && config.ProfileSettings(name) == nil {
+			return fmt.Errorf("profile '%s' is not defined (hexa profile list)", name)
+		}
+
+		path, err := config.UserConfigPath()
+		if useLocal {
+			path, err = config.LocalConfigPath()
+		}
+		if err != nil {
+			return err
+		}
+
+		if name == config.DefaultProfile {
+			err = config.UnsetYAMLField(path, config.ProfileKey)
+			if errors.Is(err, config.ErrKeyNotFound) || errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		} else {
+			err = config.UpdateYAMLField(path, config.ProfileKey, name)
+		}
+		if err != nil {
+			return fmt.Errorf("updating %s: %w", path, err)
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Profil actif: %s (%s)\n", name, path)
+		if env := os.Getenv(config.ProfileEnv); env != "" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  %s=%s reste prioritaire\n", config.ProfileEnv, env)
+		}
+		return nil
+	},
+}
+
+func init() {
+	useCmd.Flags().BoolVar(&useLocal, "local", false, "Save the profile in .hexa.local.yml (current project) instead of ~/.hexa.yml")
+	ProfileCmd.AddCommand(useCmd)
+}
@@ -1,27 +1,201 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/env"
+	"github.com/hyphaene/hexa/internal/har"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/logger"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
 	AppVersion string
 
+	offlineFlag   bool
+	timeoutFlag   time.Duration
+	logLevelFlag  string
+	verboseFlag   bool
+	quietFlag     bool
+	traceFlag     bool
+	traceFileFlag string
+
+	// tracer records Jira exchanges with --trace or --trace-file (nil otherwise)
+	tracer *jira.Tracer
+
+	// cancelTimeout releases the --timeout deadline once the command returns (nil without --timeout)
+	cancelTimeout context.CancelFunc
+
+	// configErr is the error of config.Initialize (missing ${VAR}...), nil if the configuration loaded
+	configErr error
+
 	RootCmd = &cobra.Command{
 		Use:   "hexa",
 		Short: "Hexactitude CLI - Unified automation and scripting toolkit",
 		Long: `Hexa is a unified CLI for automation and scripting tasks.
 It replaces 22+ bash scripts with a single, distributable Go binary
 organized around functional domains (JIRA, GIT, SETUP, AI).`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := configureLogger(cmd); err != nil {
+				return err
+			}
+
+			// A broken configuration only lets through the commands used to inspect and fix it
+			if configErr != nil {
+				if !toleratesConfigError(cmd) {
+					cmd.SilenceUsage = true
+					return configErr
+				}
+				logger.Warn(configErr.Error())
+			}
+
+			// Typos and outdated keys are silently ignored by Viper: point at them
+			if !toleratesConfigError(cmd) {
+				for _, issue := range config.UnknownKeys() {
+					logger.Warn(issue.String() + ", see hexa config validate")
+				}
+				for _, result := range config.PendingMigrations() {
+					logger.Warn(fmt.Sprintf("%s: configuration version %d is outdated (%d change(s)), run hexa config migrate",
+						result.Path, result.From, len(result.Changes)))
+				}
+			}
+
+			// --trace logs every Jira exchange, --trace-file also saves them as HAR
+			if traceFlag || traceFileFlag != "" {
+				tracer = jira.NewTracer(jira.Transport())
+				jira.SetTransport(tracer)
+			}
+
+			// --offline or HEXA_OFFLINE=true serves Jira data from the local cache
+			jira.SetOffline(offlineFlag || viper.GetBool("offline"))
+
+			// --timeout or HEXA_TIMEOUT bounds the whole command, Jira calls included
+			timeout := timeoutFlag
+			if configured, ok := config.GetDuration("timeout"); ok && !cmd.Flags().Changed("timeout") {
+				timeout = configured
+			}
+			// Each Jira call is bounded by jira.timeout, or by DefaultRequestTimeout
+			requestTimeout, _ := config.GetDuration("jira.timeout")
+			if timeout > 0 {
+				requestTimeout = timeout
+			}
+			jira.SetRequestTimeout(requestTimeout)
+			if timeout > 0 {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(ctx)
+				cancelTimeout = cancel
+			}
+
+			return nil
+		},
 	}
 )
 
+func init() {
+	// Read by main before parsing (config.ConfigFlag, config.ProfileFlag): declared here for help and validation
+	RootCmd.PersistentFlags().String("config", "", "Project config file to use instead of the discovered .hexa.yml (or HEXA_CONFIG)")
+	RootCmd.PersistentFlags().String("profile", "", "Profile to apply over the configuration (or HEXA_PROFILE, see hexa profile)")
+	RootCmd.PersistentFlags().BoolVar(&offlineFlag, "offline", false, "Serve Jira data from the local cache without any network call")
+	RootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 0, "Abort the command after this duration (e.g., 30s, 2m; each Jira call is otherwise limited to jira.timeout, 30s by default)")
+	RootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "", "Log level: debug|info|warn|error (default: log.level or info)")
+	RootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show debug logs (same as --log-level debug)")
+	RootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Only show errors, without progress bars (same as --log-level error)")
+	RootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+	RootCmd.PersistentFlags().BoolVar(&traceFlag, "trace", false, "Log every Jira request and response (Authorization redacted)")
+	RootCmd.PersistentFlags().StringVar(&traceFileFlag, "trace-file", "", "Save Jira requests and responses to a HAR file (implies --trace)")
+
+	_ = RootCmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return config.Profiles(), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = RootCmd.RegisterFlagCompletionFunc("log-level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// configureLogger applies --log-level/--verbose/--quiet, then log.level and log.file from config.
+// Flags win over config; DEBUG=true is the fallback when neither is set.
+func configureLogger(cmd *cobra.Command) error {
+	opts := logger.Options{
+		Verbose: verboseFlag,
+		Quiet:   quietFlag,
+		File:    viper.GetString("log.file"),
+	}
+	switch {
+	case cmd.Flags().Changed("log-level"):
+		opts.Level = logLevelFlag
+	case verboseFlag || quietFlag:
+		// Shortcut flags win over log.level
+	case viper.IsSet("log.level"):
+		opts.Level = viper.GetString("log.level")
+	case env.Debug:
+		opts.Verbose = true
+	}
+
+	return logger.Configure(opts)
+}
+
+// SetConfigError records the error of config.Initialize, reported before running a command
+func SetConfigError(err error) {
+	configErr = err
+}
+
+// toleratesConfigError reports whether cmd runs despite an invalid configuration
+// (hexa config ..., hexa profile ..., completion, help)
+func toleratesConfigError(cmd *cobra.Command) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		if !cmd.Parent().HasParent() {
+			switch cmd.Name() {
+			case "config", "profile", "completion", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // SetVersionInfo sets the version information injected by the build system
 func SetVersionInfo(version, commit, date string) {
 	AppVersion = version
 }
 
 // Execute executes the root command.
+// Ctrl-C cancels in-flight Jira requests; the cache is left as it was.
 func Execute() error {
-	return RootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	defer logger.Close()
+	defer func() {
+		if cancelTimeout != nil {
+			cancelTimeout()
+		}
+	}()
+
+	err := RootCmd.ExecuteContext(ctx)
+
+	// Saved even when the command failed: that is when a trace is most useful
+	if tracer != nil && traceFileFlag != "" {
+		if harErr := har.Write(traceFileFlag, tracer.HAR(AppVersion)); harErr != nil {
+			logger.Error("saving trace", "error", harErr)
+		} else {
+			logger.Info(fmt.Sprintf("🛰️  Trace saved to %s", traceFileFlag))
+		}
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		return fmt.Errorf("⛔ Interrompu")
+	case errors.Is(err, context.DeadlineExceeded) && cancelTimeout != nil:
+		return fmt.Errorf("⏱️  Délai dépassé (--timeout): %w", err)
+	}
+	return err
 }
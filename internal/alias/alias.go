@@ -0,0 +1,236 @@
+// Package alias parses the command aliases of the configuration (aliases section): each alias
+// runs one or more hexa commands, with {placeholder} substitution from positional arguments.
+package alias
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ConfigKey is the configuration section defining aliases
+const ConfigKey = "aliases"
+
+// stepSeparator chains the steps of a single-line alias
+const stepSeparator = "&&"
+
+// placeholderPattern matches {name} placeholders
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z][A-Za-z0-9_-]*)\}`)
+
+// Alias is a named sequence of hexa commands
+type Alias struct {
+	Name         string
+	Steps        [][]string // Arguments of each hexa command, without "hexa"
+	Placeholders []string   // Placeholders in order of first appearance, filled by positional args
+}
+
+// Load returns the aliases defined in the configuration, sorted by name. An alias is a command
+// line ("jira ticket move {ticket} && jira ticket comment {ticket} done") or a list of them.
+func Load() ([]Alias, error) {
+	raw := viper.GetStringMap(ConfigKey)
+
+	var aliases []Alias
+	var errs []string
+	for name, value := range raw {
+		alias, err := Parse(name, value)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return aliases, fmt.Errorf("invalid aliases:\n%s", strings.Join(errs, "\n"))
+	}
+	return aliases, nil
+}
+
+// Parse builds an alias from its configuration value: a string or a list of strings
+func Parse(name string, value any) (Alias, error) {
+	alias := Alias{Name: name}
+
+	var lines []string
+	switch v := value.(type) {
+	case string:
+		lines = []string{v}
+	case []any:
+		for _, item := range v {
+			line, ok := item.(string)
+			if !ok {
+				return alias, fmt.Errorf("%s.%s: each step must be a string, got %v", ConfigKey, name, item)
+			}
+			lines = append(lines, line)
+		}
+	default:
+		return alias, fmt.Errorf("%s.%s: expected a command or a list of commands", ConfigKey, name)
+	}
+
+	for _, line := range lines {
+		steps, err := splitSteps(line)
+		if err != nil {
+			return alias, fmt.Errorf("%s.%s: %w", ConfigKey, name, err)
+		}
+		for _, step := range steps {
+			if step[0] == "hexa" && len(step) > 1 { // "hexa jira ..." is accepted too
+				step = step[1:]
+			}
+			alias.Steps = append(alias.Steps, step)
+		}
+	}
+	if len(alias.Steps) == 0 {
+		return alias, fmt.Errorf("%s.%s: empty alias", ConfigKey, name)
+	}
+
+	seen := map[string]bool{}
+	for _, step := range alias.Steps {
+		for _, arg := range step {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(arg, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					alias.Placeholders = append(alias.Placeholders, match[1])
+				}
+			}
+		}
+	}
+	return alias, nil
+}
+
+// Expand substitutes the placeholders with the first positional args and appends the other
+// args to the last step
+func (a Alias) Expand(args []string) ([][]string, error) {
+	if len(args) < len(a.Placeholders) {
+		missing := make([]string, 0, len(a.Placeholders)-len(args))
+		for _, name := range a.Placeholders[len(args):] {
+			missing = append(missing, "<"+name+">")
+		}
+		return nil, fmt.Errorf("missing argument(s) %s (usage: hexa %s)", strings.Join(missing, " "), a.Usage())
+	}
+
+	values := map[string]string{}
+	for i, name := range a.Placeholders {
+		values[name] = args[i]
+	}
+	extra := args[len(a.Placeholders):]
+
+	steps := make([][]string, len(a.Steps))
+	for i, step := range a.Steps {
+		expanded := make([]string, 0, len(step)+len(extra))
+		for _, arg := range step {
+			expanded = append(expanded, placeholderPattern.ReplaceAllStringFunc(arg, func(match string) string {
+				return values[match[1:len(match)-1]]
+			}))
+		}
+		if i == len(a.Steps)-1 {
+			expanded = append(expanded, extra...)
+		}
+		steps[i] = expanded
+	}
+	return steps, nil
+}
+
+// Usage returns the command line of the alias, e.g. "wip <ticket>"
+func (a Alias) Usage() string {
+	usage := a.Name
+	for _, name := range a.Placeholders {
+		usage += " <" + name + ">"
+	}
+	return usage
+}
+
+// String returns the steps of the alias as a command line
+func (a Alias) String() string {
+	lines := make([]string, len(a.Steps))
+	for i, step := range a.Steps {
+		quoted := make([]string, len(step))
+		for j, arg := range step {
+			quoted[j] = quote(arg)
+		}
+		lines[i] = strings.Join(quoted, " ")
+	}
+	return strings.Join(lines, " "+stepSeparator+" ")
+}
+
+// splitSteps splits a command line into arguments like a shell would (quotes and backslash
+// escapes), then into steps at each unquoted &&
+func splitSteps(line string) ([][]string, error) {
+	var steps [][]string
+	var step []string
+	var current strings.Builder
+	inToken, quoted := false, false
+	var openQuote rune
+
+	flush := func() {
+		if !inToken {
+			return
+		}
+		if !quoted && current.String() == stepSeparator {
+			if len(step) == 0 {
+				steps = append(steps, nil) // Reported below
+			} else {
+				steps = append(steps, step)
+			}
+			step = nil
+		} else {
+			step = append(step, current.String())
+		}
+		current.Reset()
+		inToken, quoted = false, false
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case openQuote != 0:
+			if r == openQuote {
+				openQuote = 0
+			} else if r == '\\' && openQuote == '"' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			openQuote, inToken, quoted = r, true, true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if openQuote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	flush()
+	if len(step) > 0 {
+		steps = append(steps, step)
+	} else if len(steps) > 0 {
+		steps = append(steps, nil)
+	}
+
+	for _, s := range steps {
+		if len(s) == 0 {
+			return nil, fmt.Errorf("empty step around %s in %q", stepSeparator, line)
+		}
+	}
+	return steps, nil
+}
+
+// quote quotes an argument for display when it needs it
+func quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\") && arg != stepSeparator {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
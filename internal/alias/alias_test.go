@@ -0,0 +1,163 @@
+package alias
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name             string
+		value            any
+		wantSteps        [][]string
+		wantPlaceholders []string
+		wantErr          string
+	}{
+		{
+			name:      "single command",
+			value:     "jira sprint --filter=me",
+			wantSteps: [][]string{{"jira", "sprint", "--filter=me"}},
+		},
+		{
+			name:      "hexa prefix",
+			value:     "hexa jira sprint",
+			wantSteps: [][]string{{"jira", "sprint"}},
+		},
+		{
+			name:             "chained with placeholders",
+			value:            "jira ticket move {ticket} 'In Progress' && jira ticket comment {ticket} \"started {when}\"",
+			wantSteps:        [][]string{{"jira", "ticket", "move", "{ticket}", "In Progress"}, {"jira", "ticket", "comment", "{ticket}", "started {when}"}},
+			wantPlaceholders: []string{"ticket", "when"},
+		},
+		{
+			name:      "list of commands",
+			value:     []any{"cache clear", "jira sprint"},
+			wantSteps: [][]string{{"cache", "clear"}, {"jira", "sprint"}},
+		},
+		{
+			name:      "quoted separator",
+			value:     `jira ticket comment X '&&' a\ b "say \"hi\""`,
+			wantSteps: [][]string{{"jira", "ticket", "comment", "X", "&&", "a b", `say "hi"`}},
+		},
+		{name: "unterminated quote", value: "jira ticket comment 'oops", wantErr: "unterminated quote"},
+		{name: "empty step", value: "jira sprint && && cache clear", wantErr: "empty step"},
+		{name: "trailing separator", value: "jira sprint &&", wantErr: "empty step"},
+		{name: "empty", value: "", wantErr: "empty alias"},
+		{name: "not a string", value: []any{"jira sprint", 42}, wantErr: "each step must be a string"},
+		{name: "section", value: map[string]any{"a": "b"}, wantErr: "expected a command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alias, err := Parse("wip", tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(alias.Steps, tt.wantSteps) {
+				t.Errorf("steps = %q, want %q", alias.Steps, tt.wantSteps)
+			}
+			if !reflect.DeepEqual(alias.Placeholders, tt.wantPlaceholders) {
+				t.Errorf("placeholders = %q, want %q", alias.Placeholders, tt.wantPlaceholders)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	alias, err := Parse("wip", "jira ticket move {ticket} {status} && jira ticket comment {ticket} 'moved to {status}'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name: "placeholders",
+			args: []string{"PROJ-1", "Done"},
+			want: [][]string{{"jira", "ticket", "move", "PROJ-1", "Done"}, {"jira", "ticket", "comment", "PROJ-1", "moved to Done"}},
+		},
+		{
+			name: "extra args on the last step",
+			args: []string{"PROJ-1", "Done", "--offline"},
+			want: [][]string{{"jira", "ticket", "move", "PROJ-1", "Done"}, {"jira", "ticket", "comment", "PROJ-1", "moved to Done", "--offline"}},
+		},
+		{
+			name: "values are not expanded again",
+			args: []string{"{status}", "Done"},
+			want: [][]string{{"jira", "ticket", "move", "{status}", "Done"}, {"jira", "ticket", "comment", "{status}", "moved to Done"}},
+		},
+		{name: "missing args", args: []string{"PROJ-1"}, wantErr: "missing argument(s) <status> (usage: hexa wip <ticket> <status>)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := alias.Expand(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(steps, tt.want) {
+				t.Errorf("Expand() = %q, want %q", steps, tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, line := range []string{
+		"jira sprint --filter=me",
+		"jira ticket move {ticket} 'In Progress' && jira ticket comment {ticket} 'it'\\''s done'",
+		"jira ticket comment X '&&' ''",
+	} {
+		alias, err := Parse("a", line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reparsed, err := Parse("a", alias.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", alias.String(), err)
+		}
+		if !reflect.DeepEqual(reparsed.Steps, alias.Steps) {
+			t.Errorf("String() = %q does not parse back to %q", alias.String(), alias.Steps)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	viper.Set(ConfigKey, map[string]any{
+		"wip":    "jira ticket move {ticket} 'In Progress'",
+		"me":     "jira sprint --filter=me",
+		"broken": "jira 'oops",
+	})
+	t.Cleanup(func() { viper.Set(ConfigKey, nil) })
+
+	aliases, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "aliases.broken") {
+		t.Errorf("Load() error = %v, want one naming aliases.broken", err)
+	}
+	var names []string
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+	if want := []string{"me", "wip"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Load() = %q, want the valid aliases %q", names, want)
+	}
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// lockTimeout is how long a process waits for another one to finish refreshing an entry
+	lockTimeout = 2 * time.Minute
+	// lockPollInterval is how often a busy lock is retried
+	lockPollInterval = 50 * time.Millisecond
+
+	lockExt = ".lock"
+	tempExt = ".tmp"
+)
+
+// errLockBusy is returned by tryLock when another process holds the lock
+var errLockBusy = errors.New("lock is held by another process")
+
+// writeFileAtomic writes data to a temporary file in the same directory, then renames it
+// over path so that readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*"+tempExt)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// fileLock is an advisory lock on <path>.lock, shared by every hexa process
+type fileLock struct {
+	file *os.File
+	path string
+}
+
+// lockFile acquires the advisory lock of path, waiting up to lockTimeout for other holders
+// unless ctx is canceled first
+func lockFile(ctx context.Context, path string) (*fileLock, error) {
+	lockPath := path + lockExt
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		lock, err := tryLock(lockPath)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, errLockBusy) {
+			return nil, fmt.Errorf("failed to lock %s: %w", filepath.Base(path), err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock on %s", filepath.Base(path))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/spf13/viper"
+)
+
+// DefaultMaxDelta is the number of updated tickets above which a full refresh is used instead
+const DefaultMaxDelta = 100
+
+// IncrementalEnabled reports whether sprint refreshes may be incremental (jira.incremental.enabled, default true)
+func IncrementalEnabled() bool {
+	if !viper.IsSet("jira.incremental.enabled") {
+		return true
+	}
+	return viper.GetBool("jira.incremental.enabled")
+}
+
+// IncrementalFetcher returns a SprintFetcher that only downloads the tickets updated since the
+// previous entry was cached, merges them into it and drops tickets removed from the sprint.
+// It falls back to full when there is no previous entry, when more than jira.incremental.maxDelta
+// tickets changed, or when the searches disagree (a sprint ticket neither cached nor updated).
+func IncrementalFetcher(previous *CacheEntry, full SprintFetcher) SprintFetcher {
+	return func(ctx context.Context) ([]jira.Ticket, int, error) {
+		if previous == nil || !IncrementalEnabled() {
+			return full(ctx)
+		}
+
+		maxDelta := DefaultMaxDelta
+		if viper.IsSet("jira.incremental.maxDelta") {
+			maxDelta = viper.GetInt("jira.incremental.maxDelta")
+		}
+
+		updated, err := jira.FetchSprintTicketsUpdatedSince(ctx, previous.SprintID, previous.CachedAt, maxDelta)
+		if errors.Is(err, jira.ErrTooManyResults) {
+			return full(ctx)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		keys, err := jira.FetchSprintIssueKeys(ctx, previous.SprintID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		tickets, ok := mergeTickets(previous.Issues, updated, keys)
+		if !ok {
+			return full(ctx)
+		}
+		return tickets, len(tickets), nil
+	}
+}
+
+// mergeTickets replaces cached tickets by their updated version, keeps the cached order,
+// appends tickets new to the sprint and drops those whose key is no longer listed.
+// It reports false when a listed key is neither cached nor updated: the merge would lose it.
+func mergeTickets(cached []jira.Ticket, updated []jira.Ticket, keys []string) ([]jira.Ticket, bool) {
+	inSprint := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		inSprint[key] = true
+	}
+	updatedByKey := make(map[string]jira.Ticket, len(updated))
+	for _, ticket := range updated {
+		updatedByKey[ticket.Key] = ticket
+	}
+
+	merged := make([]jira.Ticket, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, ticket := range cached {
+		if !inSprint[ticket.Key] {
+			continue // Removed from the sprint
+		}
+		if fresh, ok := updatedByKey[ticket.Key]; ok {
+			ticket = fresh
+		}
+		merged = append(merged, ticket)
+		seen[ticket.Key] = true
+	}
+	for _, ticket := range updated {
+		if inSprint[ticket.Key] && !seen[ticket.Key] {
+			merged = append(merged, ticket)
+			seen[ticket.Key] = true
+		}
+	}
+	for _, key := range keys {
+		if !seen[key] {
+			return nil, false
+		}
+	}
+
+	return merged, true
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+)
+
+func ticket(key, status string) jira.Ticket {
+	return jira.Ticket{Key: key, Fields: jira.Fields{Summary: key, Status: jira.Status{Name: status}}}
+}
+
+func TestMergeTickets(t *testing.T) {
+	cached := []jira.Ticket{ticket("A", "To Do"), ticket("B", "To Do"), ticket("C", "To Do")}
+
+	tests := []struct {
+		name    string
+		updated []jira.Ticket
+		keys    []string
+		want    []jira.Ticket
+		wantOK  bool
+	}{
+		{
+			name:   "nothing changed",
+			keys:   []string{"A", "B", "C"},
+			want:   cached,
+			wantOK: true,
+		},
+		{
+			name:    "updated ticket keeps its place",
+			updated: []jira.Ticket{ticket("B", "Done")},
+			keys:    []string{"A", "B", "C"},
+			want:    []jira.Ticket{ticket("A", "To Do"), ticket("B", "Done"), ticket("C", "To Do")},
+			wantOK:  true,
+		},
+		{
+			name:    "new ticket appended",
+			updated: []jira.Ticket{ticket("D", "New")},
+			keys:    []string{"A", "B", "C", "D"},
+			want:    []jira.Ticket{ticket("A", "To Do"), ticket("B", "To Do"), ticket("C", "To Do"), ticket("D", "New")},
+			wantOK:  true,
+		},
+		{
+			name:   "removed ticket dropped",
+			keys:   []string{"A", "C"},
+			want:   []jira.Ticket{ticket("A", "To Do"), ticket("C", "To Do")},
+			wantOK: true,
+		},
+		{
+			name:    "updated ticket out of the sprint",
+			updated: []jira.Ticket{ticket("B", "Done")},
+			keys:    []string{"A", "C"},
+			want:    []jira.Ticket{ticket("A", "To Do"), ticket("C", "To Do")},
+			wantOK:  true,
+		},
+		{
+			name:   "listed key neither cached nor updated",
+			keys:   []string{"A", "B", "C", "E"},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mergeTickets(cached, tt.updated, tt.keys)
+			if ok != tt.wantOK {
+				t.Fatalf("mergeTickets() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeTickets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIncrementalFetcherMatchesFullFetch refreshes a sprint larger than a page both ways:
+// the merged result must be what a full fetch returns, without falling back to it
+func TestIncrementalFetcherMatchesFullFetch(t *testing.T) {
+	jira.SetOffline(false)
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.Configure()
+
+	const sprintID = 7
+	for i := range 130 {
+		server.AddIssue(sprintID, ticket(fmt.Sprintf("PROJ-%d", i), "To Do"))
+	}
+	server.Backdate(time.Hour)
+
+	ctx := context.Background()
+	fullCalls := 0
+	full := func(ctx context.Context) ([]jira.Ticket, int, error) {
+		fullCalls++
+		return jira.FetchSprintTickets(ctx, sprintID)
+	}
+
+	tickets, total, err := full(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := &CacheEntry{SprintID: sprintID, CachedAt: time.Now().Add(-30 * time.Minute), Total: total, Issues: tickets}
+
+	// Changes made in Jira since the previous refresh, on every page
+	for _, key := range []string{"PROJ-3", "PROJ-75", "PROJ-129"} {
+		server.UpdateIssue(key, func(ticket *jira.Ticket) { ticket.Fields.Status.Name = "In Progress" })
+	}
+	server.RemoveIssue(sprintID, "PROJ-60")
+	server.AddIssue(sprintID, ticket("PROJ-200", "New"))
+
+	fullCalls = 0
+	got, gotTotal, err := IncrementalFetcher(previous, full)(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullCalls != 0 {
+		t.Fatalf("incremental refresh fell back to a full fetch")
+	}
+
+	want, wantTotal, err := full(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotTotal != wantTotal || len(got) != len(want) {
+		t.Fatalf("incremental refresh: %d tickets (total %d), full fetch: %d (total %d)", len(got), gotTotal, len(want), wantTotal)
+	}
+	for i := range want {
+		if got[i].Key != want[i].Key || got[i].Fields.Status != want[i].Fields.Status {
+			t.Errorf("ticket %d: incremental %s (%s), full %s (%s)",
+				i, got[i].Key, got[i].Fields.Status.Name, want[i].Key, want[i].Fields.Status.Name)
+		}
+	}
+}
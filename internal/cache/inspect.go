@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/config"
+)
+
+// Info describes a file stored in the cache directory
+type Info struct {
+	Namespace string    // Cache namespace, "snapshots" for sprint history
+	Key       string    // Key within the namespace (sprint ID for tickets)
+	Path      string    // Absolute file path
+	Size      int64     // File size in bytes
+	CachedAt  time.Time // Timestamp when the entry was written
+	ExpiresAt time.Time // Zero for entries that never expire (snapshots, unreadable files)
+}
+
+// IsExpired reports whether the entry has an expiry in the past
+func (i *Info) IsExpired() bool {
+	return !i.ExpiresAt.IsZero() && time.Now().After(i.ExpiresAt)
+}
+
+// Dir returns the cache directory of the active profile (~/.hexa/cache without profile)
+func Dir() (string, error) {
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, CacheDirName), nil
+}
+
+// List returns every file stored in the cache directory, sorted by namespace and key
+func List() ([]Info, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []Info
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir // No cache yet
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, lockExt) || strings.HasSuffix(path, tempExt) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		info := Info{Path: path, Size: fileInfo.Size(), CachedAt: fileInfo.ModTime()}
+		describe(&info, filepath.ToSlash(rel))
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache directory: %w", err)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Namespace != infos[j].Namespace {
+			return infos[i].Namespace < infos[j].Namespace
+		}
+		return infos[i].Key < infos[j].Key
+	})
+	return infos, nil
+}
+
+// ClearAll removes the whole cache directory, sprint history included
+func ClearAll() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove cache directory: %w", err)
+	}
+
+	return nil
+}
+
+// ClearSprint removes the cached tickets of a sprint (its snapshot history is kept)
+func ClearSprint(sprintID int) error {
+	cachePath, err := getCachePath(sprintID)
+	if err != nil {
+		return fmt.Errorf("failed to get cache path: %w", err)
+	}
+
+	if err := os.Remove(cachePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no cached tickets for sprint %d", sprintID)
+		}
+		return fmt.Errorf("failed to remove cache file: %w", err)
+	}
+
+	return nil
+}
+
+// ClearExpired removes every expired entry and returns the removed ones
+func ClearExpired() ([]Info, error) {
+	infos, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Info
+	for _, info := range infos {
+		if !info.IsExpired() {
+			continue
+		}
+		if err := os.Remove(info.Path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		removed = append(removed, info)
+	}
+
+	return removed, nil
+}
+
+// describe fills namespace, key and expiry from the file location and content
+func describe(info *Info, rel string) {
+	parts := strings.Split(rel, "/")
+
+	switch {
+	case parts[0] == SnapshotDirName:
+		info.Namespace = SnapshotDirName
+		info.Key = strings.TrimSuffix(strings.Join(parts[1:], "/"), snapshotExt)
+	case len(parts) == 1 && strings.HasPrefix(rel, "sprint_") && strings.HasSuffix(rel, ".json"):
+		info.Namespace = NamespaceTickets
+		info.Key = strings.TrimSuffix(strings.TrimPrefix(rel, "sprint_"), ".json")
+		var entry CacheEntry
+		if readJSON(info.Path, &entry) == nil {
+			info.CachedAt = entry.CachedAt
+			info.ExpiresAt = entry.CachedAt.Add(time.Duration(entry.TTLSeconds) * time.Second)
+		}
+	case len(parts) == 2 && strings.HasSuffix(rel, ".json"):
+		info.Namespace = parts[0]
+		info.Key = strings.TrimSuffix(parts[1], ".json")
+		var entry Entry
+		if readJSON(info.Path, &entry) == nil {
+			info.Key = entry.Key
+			info.CachedAt = entry.CachedAt
+			info.ExpiresAt = entry.CachedAt.Add(time.Duration(entry.TTLSeconds) * time.Second)
+		}
+	default:
+		info.Namespace = "other"
+		info.Key = rel
+	}
+}
+
+// readJSON decodes a JSON file
+func readJSON(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyphaene/hexa/internal/jira"
+)
+
+// seedCache writes sprint 7 tickets (with their snapshot), a valid user and an expired status
+func seedCache(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	setConfig(t, map[string]any{"cache.ttl.statuses": 0})
+
+	if err := WriteCache(7, []jira.Ticket{{Key: "PROJ-1"}}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(NamespaceUsers, "me", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(NamespaceStatuses, "all", []string{"Done"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// namespaces returns the namespace/key of each listed entry
+func namespaces(t *testing.T) []string {
+	t.Helper()
+	infos, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, info := range infos {
+		got = append(got, info.Namespace+"/"+info.Key)
+	}
+	return got
+}
+
+func TestList(t *testing.T) {
+	seedCache(t)
+
+	infos, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"statuses/all": true, "tickets/7": false, "users/me": false}
+	snapshots := 0
+	for _, info := range infos {
+		if info.Namespace == SnapshotDirName {
+			snapshots++
+			continue
+		}
+		expired, ok := want[info.Namespace+"/"+info.Key]
+		if !ok {
+			t.Errorf("unexpected entry %s/%s", info.Namespace, info.Key)
+			continue
+		}
+		if info.IsExpired() != expired {
+			t.Errorf("%s/%s expired = %v, want %v", info.Namespace, info.Key, info.IsExpired(), expired)
+		}
+		delete(want, info.Namespace+"/"+info.Key)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing entries: %v", want)
+	}
+	if snapshots == 0 {
+		t.Error("snapshot history not listed")
+	}
+}
+
+func TestListEmptyCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if got := namespaces(t); len(got) != 0 {
+		t.Errorf("List() = %v, want nothing", got)
+	}
+}
+
+func TestClear(t *testing.T) {
+	tests := []struct {
+		name  string
+		clear func() error
+		want  []string // Entries left, snapshots excepted
+	}{
+		{
+			name:  "sprint",
+			clear: func() error { return ClearSprint(7) },
+			want:  []string{"statuses/all", "users/me"},
+		},
+		{
+			name: "expired",
+			clear: func() error {
+				_, err := ClearExpired()
+				return err
+			},
+			want: []string{"tickets/7", "users/me"},
+		},
+		{
+			name:  "all",
+			clear: ClearAll,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seedCache(t)
+			if err := tt.clear(); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			snapshots := false
+			for _, entry := range namespaces(t) {
+				if strings.HasPrefix(entry, SnapshotDirName+"/") {
+					snapshots = true
+					continue
+				}
+				got = append(got, entry)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("entries left = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("entries left = %v, want %v", got, tt.want)
+				}
+			}
+			// Only --all removes the sprint history
+			if snapshots == (tt.name == "all") {
+				t.Errorf("snapshots kept = %v after clearing %s", snapshots, tt.name)
+			}
+		})
+	}
+}
+
+func TestClearSprintWithoutCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := ClearSprint(7); err == nil {
+		t.Error("ClearSprint() succeeded without cached tickets")
+	}
+}
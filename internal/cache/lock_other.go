@@ -0,0 +1,34 @@
+//go:build !unix
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// staleLockAge is the age after which a lock file left by a crashed process is ignored
+const staleLockAge = lockTimeout
+
+// tryLock creates lockPath exclusively; the file existing means another process holds the lock
+func tryLock(lockPath string) (*fileLock, error) {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+		}
+		return nil, errLockBusy
+	}
+
+	return &fileLock{file: file, path: lockPath}, nil
+}
+
+// Unlock releases the lock by removing the lock file
+func (l *fileLock) Unlock() error {
+	_ = l.file.Close()
+	return os.Remove(l.path)
+}
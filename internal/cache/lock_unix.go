@@ -0,0 +1,33 @@
+//go:build unix
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes a non-blocking flock on lockPath, released by the kernel if the process dies
+func tryLock(lockPath string) (*fileLock, error) {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLockBusy
+		}
+		return nil, err
+	}
+
+	return &fileLock{file: file, path: lockPath}, nil
+}
+
+// Unlock releases the lock (the lock file is kept to avoid racing with waiters)
+func (l *fileLock) Unlock() error {
+	_ = syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	return l.file.Close()
+}
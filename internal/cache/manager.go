@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,10 +12,10 @@ import (
 )
 
 const (
-	// DefaultTTL is the default cache TTL in seconds (5 minutes)
+	// DefaultTTL is the default sprint tickets TTL in seconds (5 minutes), see cache.ttl.tickets
 	DefaultTTL = 300
-	// CacheDirName is the cache directory name under home directory
-	CacheDirName = ".hexa/cache"
+	// CacheDirName is the cache directory name under the state directory of the profile (config.StateDir)
+	CacheDirName = "cache"
 )
 
 // ReadCache reads cached sprint ticket data from filesystem
@@ -42,7 +43,18 @@ func ReadCache(sprintID int) (*CacheEntry, error) {
 
 // WriteCache writes sprint ticket data to filesystem cache
 func WriteCache(sprintID int, tickets []jira.Ticket, total int) error {
-	cachePath, err := getCachePath(sprintID)
+	return writeEntry(&CacheEntry{
+		SprintID:   sprintID,
+		CachedAt:   time.Now(),
+		TTLSeconds: int(TTL(NamespaceTickets).Seconds()),
+		Total:      total,
+		Issues:     tickets,
+	})
+}
+
+// writeEntry atomically writes a sprint cache entry and records it in the sprint history
+func writeEntry(entry *CacheEntry) error {
+	cachePath, err := getCachePath(entry.SprintID)
 	if err != nil {
 		return fmt.Errorf("failed to get cache path: %w", err)
 	}
@@ -53,26 +65,68 @@ func WriteCache(sprintID int, tickets []jira.Ticket, total int) error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	entry := CacheEntry{
-		SprintID:   sprintID,
-		CachedAt:   time.Now(),
-		TTLSeconds: DefaultTTL,
-		Total:      total,
-		Issues:     tickets,
-	}
-
 	data, err := json.MarshalIndent(entry, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache entry: %w", err)
 	}
 
-	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+	if err := writeFileAtomic(cachePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
 
+	// Keep a copy in the sprint history for later replay
+	if err := AppendSnapshot(entry); err != nil {
+		return fmt.Errorf("failed to append snapshot: %w", err)
+	}
+
 	return nil
 }
 
+// SprintFetcher fetches all tickets of a sprint and the sprint total
+type SprintFetcher func(ctx context.Context) ([]jira.Ticket, int, error)
+
+// RefreshSprint fetches sprint tickets and writes them to the cache.
+// Concurrent refreshes of the same sprint (e.g. a status line polling pulse while fetch runs)
+// are coalesced: callers wait on a file lock for the in-flight refresh, and reuse its entry
+// if it was written after notBefore instead of calling the API again.
+// On a cache write failure, the fetched entry is returned along with the error.
+// If ctx is canceled, the cache is left untouched.
+func RefreshSprint(ctx context.Context, sprintID int, notBefore time.Time, fetch SprintFetcher) (*CacheEntry, error) {
+	cachePath, err := getCachePath(sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache path: %w", err)
+	}
+
+	lock, err := lockFile(ctx, cachePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	// Another process may have refreshed the sprint while we were waiting
+	if current, err := ReadCache(sprintID); err == nil && current != nil && current.CachedAt.After(notBefore) {
+		return current, nil
+	}
+
+	tickets, total, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entry := &CacheEntry{
+		SprintID:   sprintID,
+		CachedAt:   time.Now(),
+		TTLSeconds: int(TTL(NamespaceTickets).Seconds()),
+		Total:      total,
+		Issues:     tickets,
+	}
+
+	return entry, writeEntry(entry)
+}
+
 // ShouldRefresh determines if cache should be refreshed
 func ShouldRefresh(entry *CacheEntry, noCache bool) bool {
 	if noCache {
@@ -81,15 +135,53 @@ func ShouldRefresh(entry *CacheEntry, noCache bool) bool {
 	if entry == nil {
 		return true // Cache miss
 	}
+	if jira.IsOffline() {
+		return false // Offline: serve whatever is cached, even expired
+	}
 	return entry.IsExpired() // TTL expired
 }
 
 // getCachePath returns the full path to cache file for a sprint
 func getCachePath(sprintID int) (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := Dir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("sprint_%d.json", sprintID)), nil
+}
+
+// FindTicket looks up a ticket in the most recent cached sprint containing it (nil if not cached)
+func FindTicket(issueKey string) (*jira.Ticket, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "sprint_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache files: %w", err)
+	}
+
+	var found *jira.Ticket
+	var foundAt time.Time
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry CacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil || !entry.CachedAt.After(foundAt) {
+			continue
+		}
+		for i := range entry.Issues {
+			if entry.Issues[i].Key == issueKey {
+				found = &entry.Issues[i]
+				foundAt = entry.CachedAt
+				break
+			}
+		}
 	}
 
-	return filepath.Join(home, CacheDirName, fmt.Sprintf("sprint_%d.json", sprintID)), nil
+	return found, nil
 }
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/jira"
+)
+
+// countingFetcher returns a SprintFetcher counting its calls, each one taking delay
+func countingFetcher(calls *atomic.Int32, delay time.Duration, tickets ...jira.Ticket) SprintFetcher {
+	return func(ctx context.Context) ([]jira.Ticket, int, error) {
+		calls.Add(1)
+		time.Sleep(delay)
+		return tickets, len(tickets), nil
+	}
+}
+
+func TestRefreshSprintCoalescesConcurrentRefreshes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	const refreshes = 8
+
+	var calls atomic.Int32
+	fetch := countingFetcher(&calls, 100*time.Millisecond, ticket("A", "To Do"), ticket("B", "Done"))
+	notBefore := time.Now()
+
+	var wg sync.WaitGroup
+	entries := make([]*CacheEntry, refreshes)
+	errs := make([]error, refreshes)
+	for i := range refreshes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries[i], errs[i] = RefreshSprint(context.Background(), 7, notBefore, fetch)
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fetched %d times, want 1", got)
+	}
+	for i := range refreshes {
+		if errs[i] != nil {
+			t.Fatalf("refresh %d: %v", i, errs[i])
+		}
+		if len(entries[i].Issues) != 2 || !entries[i].CachedAt.Equal(entries[0].CachedAt) {
+			t.Errorf("refresh %d = %d tickets cached at %v, want the entry of the single fetch", i, len(entries[i].Issues), entries[i].CachedAt)
+		}
+	}
+}
+
+func TestRefreshSprint(t *testing.T) {
+	failure := errors.New("jira is down")
+
+	tests := []struct {
+		name      string
+		cachedAgo time.Duration // Age of an existing entry, 0 for none
+		notBefore time.Duration // notBefore, relative to now
+		fetchErr  error
+		wantCalls int32
+		wantErr   error
+	}{
+		{name: "no cache", wantCalls: 1},
+		{name: "entry older than notBefore", cachedAgo: time.Hour, notBefore: -time.Minute, wantCalls: 1},
+		{name: "entry written after notBefore", cachedAgo: time.Second, notBefore: -time.Minute, wantCalls: 0},
+		{name: "fetch failure", fetchErr: failure, wantCalls: 1, wantErr: failure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			if tt.cachedAgo > 0 {
+				entry := &CacheEntry{SprintID: 7, CachedAt: time.Now().Add(-tt.cachedAgo), Issues: []jira.Ticket{ticket("OLD", "To Do")}}
+				if err := writeEntry(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var calls atomic.Int32
+			fetch := func(ctx context.Context) ([]jira.Ticket, int, error) {
+				calls.Add(1)
+				return []jira.Ticket{ticket("NEW", "To Do")}, 1, tt.fetchErr
+			}
+			entry, err := RefreshSprint(context.Background(), 7, time.Now().Add(tt.notBefore), fetch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefreshSprint() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("fetched %d times, want %d", got, tt.wantCalls)
+			}
+			if err != nil {
+				return
+			}
+
+			cached, err := ReadCache(7)
+			if err != nil || cached == nil {
+				t.Fatalf("ReadCache() = %v, %v", cached, err)
+			}
+			if cached.Issues[0].Key != entry.Issues[0].Key {
+				t.Errorf("cache holds %s, RefreshSprint returned %s", cached.Issues[0].Key, entry.Issues[0].Key)
+			}
+		})
+	}
+}
+
+func TestRefreshSprintCanceledWhileWaiting(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cachePath, err := getCachePath(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := lockFile(context.Background(), cachePath) // Another refresh in flight
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockPollInterval)
+	defer cancel()
+	var calls atomic.Int32
+	if _, err := RefreshSprint(ctx, 7, time.Now(), countingFetcher(&calls, 0)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RefreshSprint() error = %v, want the context deadline", err)
+	}
+	if calls.Load() != 0 {
+		t.Error("fetched while another refresh held the lock")
+	}
+}
+
+func TestListSkipsLockAndTemporaryFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := WriteCache(7, []jira.Ticket{{Key: "PROJ-1"}}, 1); err != nil {
+		t.Fatal(err)
+	}
+	cachePath, err := getCachePath(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{cachePath + lockExt, cachePath + tempExt} {
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if strings.HasSuffix(info.Path, lockExt) || strings.HasSuffix(info.Path, tempExt) {
+			t.Errorf("List() includes %s", info.Path)
+		}
+	}
+}
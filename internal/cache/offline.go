@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/spf13/viper"
+)
+
+// ErrNoOfflineData is returned when offline mode needs a value that was never cached
+var ErrNoOfflineData = errors.New("no cached data available offline")
+
+// BoardID returns the configured board ID, or the board ID resolved from jira.boardName
+func BoardID(ctx context.Context) (int, error) {
+	boardName := viper.GetString("jira.boardName")
+	if viper.IsSet("jira.boardId") || boardName == "" {
+		return jira.ResolveBoardID(ctx)
+	}
+
+	return Remember(ctx, NamespaceBoards, boardName, jira.ResolveBoardID)
+}
+
+// CurrentSprintID returns the active sprint ID of the configured board
+func CurrentSprintID(ctx context.Context) (int, error) {
+	boardID, err := BoardID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return Remember(ctx, NamespaceSprints, fmt.Sprintf("board_%d_active", boardID), func(ctx context.Context) (int, error) {
+		return jira.GetActiveSprintId(ctx, boardID)
+	})
+}
+
+// CurrentUser returns the authenticated user profile
+func CurrentUser(ctx context.Context) (*jira.UserProfile, error) {
+	return Remember(ctx, NamespaceUsers, "me", jira.FetchCurrentUser)
+}
+
+// Issue returns a single ticket
+func Issue(ctx context.Context, issueKey string) (*jira.Ticket, error) {
+	return Remember(ctx, NamespaceIssues, issueKey, func(ctx context.Context) (*jira.Ticket, error) {
+		return jira.FetchIssue(ctx, issueKey)
+	})
+}
+
+// Transitions returns the transitions currently available on a ticket
+func Transitions(ctx context.Context, issueKey string) ([]jira.Transition, error) {
+	return Remember(ctx, NamespaceTransitions, issueKey, func(ctx context.Context) ([]jira.Transition, error) {
+		return jira.FetchTransitions(ctx, issueKey)
+	})
+}
+
+// Statuses returns the statuses defined in Jira
+func Statuses(ctx context.Context) ([]jira.Status, error) {
+	return Remember(ctx, NamespaceStatuses, "all", jira.FetchStatuses)
+}
+
+// InvalidateIssue drops the cached data of a ticket after it was modified
+func InvalidateIssue(issueKey string) {
+	_ = Delete(NamespaceIssues, issueKey)
+	_ = Delete(NamespaceTransitions, issueKey)
+}
@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/spf13/viper"
+)
+
+const (
+	// SnapshotDirName is the snapshot history directory under the cache directory
+	SnapshotDirName = "snapshots"
+	// DefaultSnapshotMaxAge is how long snapshots are kept when cache.snapshots.maxAge is unset (30 days)
+	DefaultSnapshotMaxAge = 30 * 24 * time.Hour
+	// DefaultSnapshotMaxCount is how many snapshots are kept per sprint when cache.snapshots.maxCount is unset
+	DefaultSnapshotMaxCount = 500
+
+	snapshotLayout = "20060102T150405.000000000Z"
+	snapshotExt    = ".json.gz"
+)
+
+// SnapshotInfo describes a snapshot stored in a sprint history
+type SnapshotInfo struct {
+	SprintID int       // Sprint ID the snapshot belongs to
+	TakenAt  time.Time // CachedAt of the snapshotted entry
+	Path     string    // Absolute path of the compressed file
+}
+
+// AppendSnapshot stores a compressed, timestamped copy of a cache entry in its sprint history.
+// Existing snapshots are never rewritten; retention is applied after the append.
+func AppendSnapshot(entry *CacheEntry) error {
+	if !snapshotsEnabled() {
+		return nil
+	}
+
+	dir, err := getSnapshotDir(entry.SprintID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	name := entry.CachedAt.UTC().Format(snapshotLayout) + snapshotExt
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil // Same entry already recorded
+		}
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(entry); err != nil {
+		_ = gz.Close()
+		_ = file.Close()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return pruneSnapshots(entry.SprintID)
+}
+
+// ListSnapshots returns the snapshots recorded for a sprint, oldest first.
+// A sprintID of 0 lists the snapshots of every sprint.
+func ListSnapshots(sprintID int) ([]SnapshotInfo, error) {
+	root, err := getSnapshotRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	if sprintID > 0 {
+		dirs = []string{fmt.Sprintf("sprint_%d", sprintID)}
+	} else {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() && strings.HasPrefix(e.Name(), "sprint_") {
+				dirs = append(dirs, e.Name())
+			}
+		}
+	}
+
+	var snapshots []SnapshotInfo
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(dir, "sprint_"))
+		if err != nil {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), snapshotExt) {
+				continue
+			}
+			takenAt, err := time.Parse(snapshotLayout, strings.TrimSuffix(f.Name(), snapshotExt))
+			if err != nil {
+				continue
+			}
+			snapshots = append(snapshots, SnapshotInfo{
+				SprintID: id,
+				TakenAt:  takenAt,
+				Path:     filepath.Join(root, dir, f.Name()),
+			})
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].TakenAt.Before(snapshots[j].TakenAt)
+	})
+	return snapshots, nil
+}
+
+// ReadSnapshotAt returns the latest snapshot taken at or before the given time.
+// A sprintID of 0 searches every sprint, which replays whatever sprint was being fetched at that time.
+func ReadSnapshotAt(sprintID int, at time.Time) (*CacheEntry, error) {
+	snapshots, err := ListSnapshots(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].TakenAt.After(at) {
+			return ReadSnapshot(snapshots[i].Path)
+		}
+	}
+
+	if sprintID > 0 {
+		return nil, fmt.Errorf("no snapshot of sprint %d recorded before %s", sprintID, at.Format(time.RFC3339))
+	}
+	return nil, fmt.Errorf("no sprint snapshot recorded before %s", at.Format(time.RFC3339))
+}
+
+// ReadSnapshot decodes a compressed snapshot file
+func ReadSnapshot(path string) (*CacheEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("corrupted snapshot %s: %w", filepath.Base(path), err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var entry CacheEntry
+	if err := json.NewDecoder(gz).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("corrupted snapshot %s: %w", filepath.Base(path), err)
+	}
+
+	return &entry, nil
+}
+
+// pruneSnapshots removes snapshots exceeding cache.snapshots.maxAge or cache.snapshots.maxCount
+func pruneSnapshots(sprintID int) error {
+	snapshots, err := ListSnapshots(sprintID)
+	if err != nil {
+		return err
+	}
+
+	maxAge, ok := config.GetDuration("cache.snapshots.maxAge")
+	if !ok {
+		maxAge = DefaultSnapshotMaxAge
+	}
+	maxCount := DefaultSnapshotMaxCount
+	if viper.IsSet("cache.snapshots.maxCount") {
+		maxCount = viper.GetInt("cache.snapshots.maxCount")
+	}
+
+	excess := len(snapshots) - maxCount
+	for i, snapshot := range snapshots {
+		tooOld := maxAge > 0 && time.Since(snapshot.TakenAt) > maxAge
+		tooMany := maxCount > 0 && i < excess
+		if !tooOld && !tooMany {
+			continue
+		}
+		if err := os.Remove(snapshot.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to prune snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// snapshotsEnabled reports whether snapshot history is turned on (cache.snapshots.enabled, default true)
+func snapshotsEnabled() bool {
+	if !viper.IsSet("cache.snapshots.enabled") {
+		return true
+	}
+	return viper.GetBool("cache.snapshots.enabled")
+}
+
+// getSnapshotRoot returns the directory holding every sprint history
+func getSnapshotRoot() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, SnapshotDirName), nil
+}
+
+// getSnapshotDir returns the history directory of a sprint
+func getSnapshotDir(sprintID int) (string, error) {
+	root, err := getSnapshotRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, fmt.Sprintf("sprint_%d", sprintID)), nil
+}
@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/spf13/viper"
+)
+
+// setConfig sets configuration keys for the test
+func setConfig(t *testing.T, values map[string]any) {
+	t.Helper()
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	t.Cleanup(func() {
+		for key := range values {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+// snapshotEntry is a sprint entry with one ticket, cached at a time
+func snapshotEntry(sprintID int, key string, cachedAt time.Time) *CacheEntry {
+	return &CacheEntry{SprintID: sprintID, CachedAt: cachedAt, Total: 1, Issues: []jira.Ticket{{Key: key}}}
+}
+
+func TestAppendSnapshot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+
+	entry := snapshotEntry(7, "PROJ-1", now)
+	for range 2 { // The same entry is recorded once
+		if err := AppendSnapshot(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots, err := ListSnapshots(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || !strings.HasSuffix(snapshots[0].Path, snapshotExt) {
+		t.Fatalf("ListSnapshots() = %v, want one compressed snapshot", snapshots)
+	}
+
+	read, err := ReadSnapshot(snapshots[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.SprintID != 7 || len(read.Issues) != 1 || read.Issues[0].Key != "PROJ-1" || !read.CachedAt.Equal(now) {
+		t.Errorf("ReadSnapshot() = %+v, want the appended entry", read)
+	}
+
+	setConfig(t, map[string]any{"cache.snapshots.enabled": false})
+	if err := AppendSnapshot(snapshotEntry(7, "PROJ-2", now.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots, _ := ListSnapshots(7); len(snapshots) != 1 {
+		t.Errorf("disabled history recorded a snapshot: %d snapshots", len(snapshots))
+	}
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		ages   []time.Duration // Ages of the appended snapshots, oldest first
+		want   int
+	}{
+		{name: "defaults", ages: []time.Duration{40 * 24 * time.Hour, time.Hour, 0}, want: 2},
+		{name: "max age in seconds", config: map[string]any{"cache.snapshots.maxAge": 7200}, ages: []time.Duration{3 * time.Hour, time.Hour, 0}, want: 2},
+		{name: "max age as a duration", config: map[string]any{"cache.snapshots.maxAge": "90m"}, ages: []time.Duration{3 * time.Hour, time.Hour, 0}, want: 2},
+		{name: "max age disabled", config: map[string]any{"cache.snapshots.maxAge": 0}, ages: []time.Duration{400 * 24 * time.Hour, 0}, want: 2},
+		{name: "max count", config: map[string]any{"cache.snapshots.maxCount": 2}, ages: []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour, 0}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			setConfig(t, tt.config)
+
+			now := time.Now()
+			for _, age := range tt.ages {
+				if err := AppendSnapshot(snapshotEntry(7, "PROJ-1", now.Add(-age))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			snapshots, err := ListSnapshots(7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(snapshots) != tt.want {
+				t.Fatalf("kept %d snapshots, want %d", len(snapshots), tt.want)
+			}
+			if latest := snapshots[len(snapshots)-1].TakenAt; now.Sub(latest) > time.Second {
+				t.Errorf("the latest snapshot was pruned, kept %v", snapshots)
+			}
+		})
+	}
+}
+
+func TestReadSnapshotAt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	start := time.Date(2026, 10, 10, 9, 0, 0, 0, time.UTC)
+	for _, entry := range []*CacheEntry{
+		snapshotEntry(7, "MORNING", start),
+		snapshotEntry(7, "NOON", start.Add(3*time.Hour)),
+		snapshotEntry(8, "OTHER", start.Add(4*time.Hour)),
+	} {
+		if err := AppendSnapshot(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		sprintID int
+		at       time.Time
+		want     string
+		wantErr  bool
+	}{
+		{name: "exact time", sprintID: 7, at: start, want: "MORNING"},
+		{name: "between snapshots", sprintID: 7, at: start.Add(2 * time.Hour), want: "MORNING"},
+		{name: "after the last snapshot", sprintID: 7, at: start.Add(48 * time.Hour), want: "NOON"},
+		{name: "other sprint ignored", sprintID: 8, at: start.Add(3 * time.Hour), wantErr: true},
+		{name: "before the history", sprintID: 7, at: start.Add(-time.Minute), wantErr: true},
+		{name: "unknown sprint", sprintID: 9, at: start.Add(48 * time.Hour), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := ReadSnapshotAt(tt.sprintID, tt.at)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadSnapshotAt() = %v, want an error", entry.Issues)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entry.Issues[0].Key != tt.want {
+				t.Errorf("ReadSnapshotAt() = %s, want %s", entry.Issues[0].Key, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprintIndex(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	setConfig(t, map[string]any{"jira.boardId": 12})
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, record := range []struct {
+		sprintID int
+		at       time.Time
+	}{{41, start}, {41, start.Add(24 * time.Hour)}, {42, start.Add(14 * 24 * time.Hour)}} {
+		if err := RecordActiveSprint(record.sprintID, record.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RecordSprintNumber(40, 39); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		want    int
+		wantErr bool
+	}{
+		{name: "first sprint", at: start.Add(7 * 24 * time.Hour), want: 41},
+		{name: "next sprint", at: start.Add(15 * 24 * time.Hour), want: 42},
+		{name: "before any fetch", at: start.Add(-time.Hour), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ActiveSprintAt(tt.at)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ActiveSprintAt() = %d, %v, want %d (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if got, err := RecordedSprintNumber(40); err != nil || got != 39 {
+		t.Errorf("RecordedSprintNumber(40) = %d, %v, want 39", got, err)
+	}
+	if _, err := RecordedSprintNumber(43); err == nil {
+		t.Error("RecordedSprintNumber() of a sprint never fetched succeeded")
+	}
+
+	// Another board has its own history
+	setConfig(t, map[string]any{"jira.boardId": 13})
+	if _, err := ActiveSprintAt(start.Add(15 * 24 * time.Hour)); err == nil {
+		t.Error("ActiveSprintAt() used the history of another board")
+	}
+	if _, err := os.Stat(mustSprintIndexPath(t)); err != nil {
+		t.Errorf("sprint index not written: %v", err)
+	}
+}
+
+func mustSprintIndexPath(t *testing.T) string {
+	t.Helper()
+	path, err := getSprintIndexPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
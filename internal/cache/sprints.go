@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// sprintIndexFileName records, next to the snapshot histories, how sprints were resolved when fetched
+const sprintIndexFileName = "sprints.json"
+
+// sprintIndex lets --at find a sprint history without calling Jira: the sprint numbers resolved
+// and the successive active sprints of each board
+type sprintIndex struct {
+	Numbers map[string]int            `json:"numbers"` // "<board>/<number>" -> sprint ID
+	Active  map[string][]activeSprint `json:"active"`  // Board -> active sprints, oldest first
+}
+
+// activeSprint is a sprint seen as the active one of its board from Since on
+type activeSprint struct {
+	SprintID int       `json:"sprintId"`
+	Since    time.Time `json:"since"`
+}
+
+// RecordSprintNumber remembers the ID a sprint number of the configured board resolved to
+func RecordSprintNumber(number, sprintID int) error {
+	return updateSprintIndex(func(index *sprintIndex) bool {
+		key := boardKey() + "/" + strconv.Itoa(number)
+		if index.Numbers[key] == sprintID {
+			return false
+		}
+		index.Numbers[key] = sprintID
+		return true
+	})
+}
+
+// RecordActiveSprint remembers that sprintID was the active sprint of the configured board at a time
+func RecordActiveSprint(sprintID int, at time.Time) error {
+	return updateSprintIndex(func(index *sprintIndex) bool {
+		board := boardKey()
+		history := index.Active[board]
+		if len(history) > 0 && history[len(history)-1].SprintID == sprintID {
+			return false
+		}
+		index.Active[board] = append(history, activeSprint{SprintID: sprintID, Since: at})
+		return true
+	})
+}
+
+// RecordedSprintNumber returns the sprint ID a sprint number of the configured board resolved to
+// when it was last fetched
+func RecordedSprintNumber(number int) (int, error) {
+	index, err := readSprintIndex()
+	if err != nil {
+		return 0, err
+	}
+	if sprintID, ok := index.Numbers[boardKey()+"/"+strconv.Itoa(number)]; ok {
+		return sprintID, nil
+	}
+	return 0, fmt.Errorf("sprint %d was never fetched: no history to replay", number)
+}
+
+// ActiveSprintAt returns the sprint that was the active one of the configured board at a time,
+// as seen by the fetches
+func ActiveSprintAt(at time.Time) (int, error) {
+	index, err := readSprintIndex()
+	if err != nil {
+		return 0, err
+	}
+	history := index.Active[boardKey()]
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].Since.After(at) {
+			return history[i].SprintID, nil
+		}
+	}
+	return 0, fmt.Errorf("no active sprint fetched before %s (use --sprint-number)", at.Format(time.RFC3339))
+}
+
+// boardKey identifies the configured board in the sprint index
+func boardKey() string {
+	if viper.IsSet("jira.boardId") {
+		return viper.GetString("jira.boardId")
+	}
+	return viper.GetString("jira.boardName")
+}
+
+// readSprintIndex reads the sprint index, empty if it does not exist yet
+func readSprintIndex() (*sprintIndex, error) {
+	index := &sprintIndex{Numbers: map[string]int{}, Active: map[string][]activeSprint{}}
+	path, err := getSprintIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, fmt.Errorf("failed to read sprint index: %w", err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("corrupted sprint index: %w", err)
+	}
+	if index.Numbers == nil {
+		index.Numbers = map[string]int{}
+	}
+	if index.Active == nil {
+		index.Active = map[string][]activeSprint{}
+	}
+	return index, nil
+}
+
+// updateSprintIndex applies update to the sprint index and writes it back if it changed
+func updateSprintIndex(update func(*sprintIndex) bool) error {
+	index, err := readSprintIndex()
+	if err != nil {
+		return err
+	}
+	if !update(index) {
+		return nil
+	}
+
+	path, err := getSprintIndexPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sprint index: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sprint index: %w", err)
+	}
+	return nil
+}
+
+// getSprintIndexPath returns the path of the sprint index
+func getSprintIndexPath() (string, error) {
+	root, err := getSnapshotRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, sprintIndexFileName), nil
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/jira"
+)
+
+// Cache namespaces, each stored in its own directory with its own TTL (cache.ttl.<namespace>)
+const (
+	NamespaceTickets     = "tickets"     // Sprint tickets (sprint_{id}.json files)
+	NamespaceBoards      = "boards"      // Board IDs resolved from board names
+	NamespaceSprints     = "sprints"     // Active sprint ID of a board
+	NamespaceUsers       = "users"       // Authenticated user profile
+	NamespaceTransitions = "transitions" // Transitions available on a ticket
+	NamespaceStatuses    = "statuses"    // Jira statuses
+	NamespaceIssues      = "issues"      // Single tickets
+)
+
+// DefaultTTLs holds the TTL in seconds of each namespace when cache.ttl.<namespace> is unset
+var DefaultTTLs = map[string]int{
+	NamespaceTickets:     DefaultTTL,
+	NamespaceBoards:      7 * 24 * 3600,
+	NamespaceSprints:     600,
+	NamespaceUsers:       24 * 3600,
+	NamespaceTransitions: 3600,
+	NamespaceStatuses:    24 * 3600,
+	NamespaceIssues:      DefaultTTL,
+}
+
+// refresh makes Remember fetch fresh values even when cached ones are still valid
+var refresh bool
+
+// SetRefresh forces Remember to bypass unexpired entries (used to warm the cache)
+func SetRefresh(enabled bool) {
+	refresh = enabled
+}
+
+// Entry represents a generic cache entry, the payload being kept as raw JSON
+type Entry struct {
+	Namespace  string          `json:"namespace"`  // Namespace the entry belongs to
+	Key        string          `json:"key"`        // Key within the namespace
+	CachedAt   time.Time       `json:"cachedAt"`   // Timestamp when the entry was written
+	TTLSeconds int             `json:"ttlSeconds"` // Time-to-live in seconds
+	Payload    json.RawMessage `json:"payload"`    // Cached value
+}
+
+// IsExpired checks if the entry has exceeded its TTL
+func (e *Entry) IsExpired() bool {
+	return time.Since(e.CachedAt) > time.Duration(e.TTLSeconds)*time.Second
+}
+
+// Age returns the time elapsed since the entry was written
+func (e *Entry) Age() time.Duration {
+	return time.Since(e.CachedAt)
+}
+
+// TTL returns the TTL of a namespace from cache.ttl.<namespace> (seconds or duration like "10m")
+func TTL(namespace string) time.Duration {
+	if ttl, ok := config.GetDuration("cache.ttl." + namespace); ok {
+		return ttl
+	}
+
+	seconds, ok := DefaultTTLs[namespace]
+	if !ok {
+		seconds = DefaultTTL
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Get reads an entry and decodes its payload into out, even if expired.
+// It returns a nil entry on cache miss.
+func Get(namespace string, key string, out any) (*Entry, error) {
+	path, err := getEntryPath(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Cache miss, not an error
+		}
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("corrupted cache file: %w", err)
+	}
+	if err := json.Unmarshal(entry.Payload, out); err != nil {
+		return nil, fmt.Errorf("corrupted cache payload: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Set writes a value in the cache with the TTL of its namespace
+func Set(namespace string, key string, value any) error {
+	path, err := getEntryPath(namespace, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache payload: %w", err)
+	}
+
+	entry := Entry{
+		Namespace:  namespace,
+		Key:        key,
+		CachedAt:   time.Now(),
+		TTLSeconds: int(TTL(namespace).Seconds()),
+		Payload:    payload,
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an entry (no error if it does not exist)
+func Delete(namespace string, key string) error {
+	path, err := getEntryPath(namespace, key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete cache file: %w", err)
+	}
+
+	return nil
+}
+
+// Remember returns the cached value of namespace/key, calling fetch and caching its result
+// when the entry is missing or expired. In offline mode, or when fetch cannot reach Jira,
+// the cached value is served even if expired.
+func Remember[T any](ctx context.Context, namespace string, key string, fetch func(context.Context) (T, error)) (T, error) {
+	var cached T
+	entry, err := Get(namespace, key, &cached)
+	if err != nil {
+		entry = nil // Treat corrupted cache as cache miss
+	}
+
+	if entry != nil && ((!entry.IsExpired() && !refresh) || jira.IsOffline()) {
+		return cached, nil
+	}
+	if entry == nil && jira.IsOffline() {
+		var zero T
+		return zero, fmt.Errorf("%s/%s: %w (%w)", namespace, key, ErrNoOfflineData, jira.ErrOffline)
+	}
+
+	// Single-flight: wait for any other process refreshing the same entry, then reuse its result
+	path, err := getEntryPath(namespace, key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	lock, err := lockFile(ctx, path)
+	if err == nil {
+		defer func() { _ = lock.Unlock() }()
+	} else if ctxErr := ctx.Err(); ctxErr != nil {
+		var zero T
+		return zero, ctxErr
+	}
+	if !refresh {
+		var fresh T
+		if current, err := Get(namespace, key, &fresh); err == nil && current != nil && !current.IsExpired() {
+			return fresh, nil
+		}
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		if entry != nil && jira.IsConnectionError(err) {
+			return cached, nil // Jira unreachable: serve the expired value
+		}
+		var zero T
+		return zero, err
+	}
+
+	_ = Set(namespace, key, value) // Non-fatal: next call will fetch again
+	return value, nil
+}
+
+// unsafeKeyChars matches characters not allowed in cache file names
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// getEntryPath returns the full path to the cache file of namespace/key
+func getEntryPath(namespace string, key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	fileName := unsafeKeyChars.ReplaceAllString(key, "_") + ".json"
+	return filepath.Join(dir, namespace, fileName), nil
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingFetch returns a Remember fetch function counting its calls
+func countingFetch(calls *int, value string) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		*calls++
+		return value, nil
+	}
+}
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		value     any
+		want      time.Duration
+	}{
+		{name: "namespace default", namespace: NamespaceBoards, want: 7 * 24 * time.Hour},
+		{name: "unknown namespace", namespace: "other", want: DefaultTTL * time.Second},
+		{name: "bare seconds", namespace: NamespaceUsers, value: 90, want: 90 * time.Second},
+		{name: "duration", namespace: NamespaceUsers, value: "10m", want: 10 * time.Minute},
+		{name: "invalid value", namespace: NamespaceSprints, value: "soon", want: 600 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != nil {
+				setConfig(t, map[string]any{"cache.ttl." + tt.namespace: tt.value})
+			}
+			if got := TTL(tt.namespace); got != tt.want {
+				t.Errorf("TTL(%q) = %v, want %v", tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRememberUsesNamespaceTTL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	setConfig(t, map[string]any{"cache.ttl.users": "1h", "cache.ttl.statuses": 0})
+	ctx := context.Background()
+
+	// users keeps its value for an hour, statuses expires right away
+	var userCalls, statusCalls int
+	for range 3 {
+		if _, err := Remember(ctx, NamespaceUsers, "me", countingFetch(&userCalls, "alice")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Remember(ctx, NamespaceStatuses, "all", countingFetch(&statusCalls, "open")); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if userCalls != 1 {
+		t.Errorf("users fetched %d times, want 1", userCalls)
+	}
+	if statusCalls != 3 {
+		t.Errorf("statuses fetched %d times, want 3", statusCalls)
+	}
+
+	var cached string
+	entry, err := Get(NamespaceUsers, "me", &cached)
+	if err != nil || entry == nil {
+		t.Fatalf("Get() = %v, %v, want the remembered entry", entry, err)
+	}
+	if cached != "alice" || entry.TTLSeconds != 3600 {
+		t.Errorf("entry = %q with TTL %ds, want %q with TTL 3600s", cached, entry.TTLSeconds, "alice")
+	}
+}
+
+func TestRememberFetchError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	errFetch := errors.New("boom")
+
+	_, err := Remember(context.Background(), NamespaceIssues, "PROJ-1", func(context.Context) (string, error) {
+		return "", errFetch
+	})
+	if !errors.Is(err, errFetch) {
+		t.Fatalf("Remember() error = %v, want %v", err, errFetch)
+	}
+
+	var cached string
+	if entry, _ := Get(NamespaceIssues, "PROJ-1", &cached); entry != nil {
+		t.Errorf("failed fetch was cached: %+v", entry)
+	}
+}
+
+func TestSetRefreshBypassesValidEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { SetRefresh(false) }) // refresh is global: never leak it to other tests
+	ctx := context.Background()
+
+	var calls int
+	fetch := countingFetch(&calls, "alice")
+	remember := func() {
+		t.Helper()
+		if _, err := Remember(ctx, NamespaceUsers, "me", fetch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remember()
+	SetRefresh(true)
+	remember()
+	remember()
+	SetRefresh(false)
+	remember()
+	if calls != 3 {
+		t.Errorf("fetched %d times, want 3 (first call and each refresh)", calls)
+	}
+}
@@ -1,27 +1,39 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
-	"github.com/hyphaene/hexa/internal/env"
+	"github.com/hyphaene/hexa/internal/logger"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
-// Initialize loads root and project configurations into the global Viper instance
-func Initialize() {
-	err := godotenv.Load()
-	if err != nil && env.Debug {
-		fmt.Println("No .env file found (this is ok)")
+// Initialize loads root and project configurations into the global Viper instance, then
+// the active profile (see applyProfile) over them.
+// ${VAR} placeholders are expanded in every file; the returned error lists the missing
+// variables, the configuration being loaded anyway (unexpanded values are left out).
+func Initialize() error {
+	preset := environNames() // Variables set before .env is loaded do not come from it
+	if err := godotenv.Load(); err != nil {
+		logger.Debug("No .env file found (this is ok)")
 	}
-	rootConfig := getRootConfig()
-	projectConfig := getProjectConfig()
-	secretProjectConfig := getSecretProjectConfig()
+	var explicitErr error
+	if path, _ := ProjectConfigPath(); explicitPath != "" && !exists(path) {
+		explicitErr = fmt.Errorf("config file %s not found (--config or %s)", path, ConfigEnv)
+	}
+
+	rootConfig, rootErr := getRootConfig()
+	projectConfig, projectErr := getProjectConfig()
+	secretProjectConfig, secretErr := getSecretProjectConfig()
+	recordLayers(preset, rootConfig, projectConfig, secretProjectConfig)
 
 	// Clear any existing config
 	viper.Reset()
@@ -29,24 +41,29 @@ func Initialize() {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	if rootConfig != nil {
-		if err := viper.MergeConfigMap(rootConfig); err != nil && env.Debug {
-			fmt.Println("Error merging root config:", err)
+		if err := viper.MergeConfigMap(rootConfig); err != nil {
+			logger.Debug("Error merging root config", "error", err)
 		}
 	}
 	if projectConfig != nil {
-		if err := viper.MergeConfigMap(projectConfig); err != nil && env.Debug {
-			fmt.Println("Error merging project config:", err)
+		if err := viper.MergeConfigMap(projectConfig); err != nil {
+			logger.Debug("Error merging project config", "error", err)
 		}
 	}
 	if secretProjectConfig != nil {
-		if err := viper.MergeConfigMap(secretProjectConfig); err != nil && env.Debug {
-			fmt.Println("Error merging secret project config:", err)
+		if err := viper.MergeConfigMap(secretProjectConfig); err != nil {
+			logger.Debug("Error merging secret project config", "error", err)
 		}
 	}
 
-	if env.Debug {
-		fmt.Println("Viper configuration initialized successfully")
+	profileErr := applyProfile()
+
+	if err := errors.Join(explicitErr, rootErr, projectErr, secretErr, profileErr); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
 	}
+
+	logger.Debug("Viper configuration initialized successfully")
+	return nil
 }
 
 // GetMergedConfig returns the complete merged configuration for debugging
@@ -54,70 +71,67 @@ func GetMergedConfig() map[string]any {
 	return viper.AllSettings()
 }
 
-func getRootConfig() map[string]any {
-	homeDir, _ := os.UserHomeDir()
-	configPath := filepath.Join(homeDir, ".hexa.yml")
-
-	if env.Debug {
-		fmt.Println("Attempting to read root config from:", configPath)
+// UserConfigPath returns the user global config file (~/.hexa.yml)
+func UserConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
 	}
+	return filepath.Join(homeDir, ".hexa.yml"), nil
+}
+
+func getRootConfig() (map[string]any, error) {
+	configPath, _ := UserConfigPath()
+
+	logger.Debug("Attempting to read root config", "path", configPath)
 
 	return getConfig(configPath)
 }
 
-func getProjectConfig() map[string]any {
-	workingDir, err := os.Getwd()
+func getProjectConfig() (map[string]any, error) {
+	configPath, err := ProjectConfigPath()
 	if err != nil {
-		if env.Debug {
-			fmt.Println("Error getting working directory:", err)
-		}
-		return nil
+		logger.Debug("Error getting working directory", "error", err)
+		return nil, nil
 	}
 
-	configPath := filepath.Join(workingDir, ".hexa.yml")
-	if env.Debug {
-		fmt.Println("Attempting to read project config from:", configPath)
-	}
+	logger.Debug("Attempting to read project config", "path", configPath)
 
 	return getConfig(configPath)
 }
 
-func getSecretProjectConfig() map[string]any {
-	workingDir, err := os.Getwd()
+func getSecretProjectConfig() (map[string]any, error) {
+	configPath, err := LocalConfigPath()
 	if err != nil {
-		if env.Debug {
-			fmt.Println("Error getting working directory:", err)
-		}
-		return nil
+		logger.Debug("Error getting working directory", "error", err)
+		return nil, nil
 	}
 
-	configPath := filepath.Join(workingDir, ".hexa.local.yml")
-	if env.Debug {
-		fmt.Println("Attempting to read project config from:", configPath)
-	}
+	logger.Debug("Attempting to read project config", "path", configPath)
 
 	return getConfig(configPath)
 }
 
-func getConfig(configPath string) map[string]any {
+func getConfig(configPath string) (map[string]any, error) {
 	// Create a new Viper instance for this config file
 	v := viper.New()
 	v.SetConfigFile(configPath)
 
 	if err := v.ReadInConfig(); err != nil {
-		if env.Debug {
-			fmt.Printf("Config file not found or error reading %s: %v\n", configPath, err)
-		}
-		return nil
+		logger.Debug("Config file not found or error reading it", "path", configPath, "error", err)
+		return nil, nil
 	}
 
-	if env.Debug {
-		fmt.Println("Successfully loaded config from:", v.ConfigFileUsed())
-		yamlBytes, err := yaml.Marshal(v.AllSettings())
-		if err == nil {
-			fmt.Printf("Config settings:\n%s\n", yamlBytes)
+	logger.Debug("Successfully loaded config", "path", v.ConfigFileUsed())
+	if logger.L().Enabled(context.Background(), slog.LevelDebug) {
+		if yamlBytes, err := yaml.Marshal(v.AllSettings()); err == nil {
+			logger.Debug(fmt.Sprintf("Config settings:\n%s", yamlBytes))
 		}
 	}
 
-	return v.AllSettings()
+	settings := v.AllSettings()
+	if err := expandSettings(settings, ""); err != nil {
+		return settings, fmt.Errorf("%s: %w", configPath, err)
+	}
+	return settings, nil
 }
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Project configuration file names, in a project directory or in its .hexa/ directory
+const (
+	ProjectFileName    = ".hexa.yml"
+	LocalFileName      = ".hexa.local.yml"
+	ProjectDirName     = ".hexa"
+	DirProjectFileName = "config.yml"
+	DirLocalFileName   = "config.local.yml"
+)
+
+// ConfigEnv points at an explicit project config file, like --config
+const ConfigEnv = "HEXA_CONFIG"
+
+// explicitPath is the project config file given by --config or HEXA_CONFIG ("" to discover it)
+var explicitPath string
+
+// project caches the discovered project files: the working directory does not change
+var project struct {
+	once      sync.Once
+	path      string
+	localPath string
+	err       error
+}
+
+// SetConfigFile uses path as the project config instead of discovering it; its local
+// counterpart is the sibling "<name>.local.yml". It must be called before Initialize.
+func SetConfigFile(path string) {
+	explicitPath = path
+	project.once = sync.Once{}
+}
+
+// ExplicitConfigFile returns the absolute path of the project config given by --config or
+// HEXA_CONFIG, "" when it is discovered
+func ExplicitConfigFile() string {
+	if explicitPath == "" {
+		return ""
+	}
+	path, _ := ProjectConfigPath()
+	return path
+}
+
+// ConfigFlag returns the value of --config in args (parsed before cobra, as the configuration
+// is loaded first), or HEXA_CONFIG
+func ConfigFlag(args []string) string {
+	if value := flagValue(args, "config"); value != "" {
+		return value
+	}
+	return os.Getenv(ConfigEnv)
+}
+
+// flagValue returns the value of the --name flag in args, "" if absent
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+name+"="); ok {
+			return value
+		}
+		if arg == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// ProjectConfigPath returns the project config file (versioned): --config, or .hexa.yml
+// (or .hexa/config.yml) in the nearest project directory, see FindProjectDir
+func ProjectConfigPath() (string, error) {
+	discoverProject()
+	return project.path, project.err
+}
+
+// LocalConfigPath returns the project local secrets file (gitignored), next to the project config
+func LocalConfigPath() (string, error) {
+	discoverProject()
+	return project.localPath, project.err
+}
+
+// discoverProject resolves the project files once
+func discoverProject() {
+	project.once.Do(func() {
+		if explicitPath != "" {
+			path, err := filepath.Abs(expandHome(explicitPath))
+			if err != nil {
+				project.err = fmt.Errorf("resolving --config: %w", err)
+				return
+			}
+			ext := filepath.Ext(path)
+			project.path = path
+			project.localPath = strings.TrimSuffix(path, ext) + ".local" + ext
+			return
+		}
+
+		workingDir, err := os.Getwd()
+		if err != nil {
+			project.err = fmt.Errorf("getting working directory: %w", err)
+			return
+		}
+
+		dir := FindProjectDir(workingDir)
+		if isDir(filepath.Join(dir, ProjectDirName)) && !isHome(dir) {
+			project.path = filepath.Join(dir, ProjectDirName, DirProjectFileName)
+			project.localPath = filepath.Join(dir, ProjectDirName, DirLocalFileName)
+			return
+		}
+		project.path = filepath.Join(dir, ProjectFileName)
+		project.localPath = filepath.Join(dir, LocalFileName)
+	})
+}
+
+// FindProjectDir walks up from start to the nearest directory holding .hexa.yml, .hexa.local.yml
+// or a .hexa/ directory. The walk stops at a git root (the directory containing .git) and never
+// reaches $HOME, whose .hexa.yml is the user config. Without any project file, the git root
+// (or start, outside of a repository) is the project directory.
+func FindProjectDir(start string) string {
+	home, _ := os.UserHomeDir()
+	fallback := start
+
+	for dir := start; ; {
+		if home != "" && dir == home {
+			break
+		}
+		if exists(filepath.Join(dir, ProjectFileName)) || exists(filepath.Join(dir, LocalFileName)) ||
+			isDir(filepath.Join(dir, ProjectDirName)) {
+			return dir
+		}
+		if exists(filepath.Join(dir, ".git")) {
+			fallback = dir
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return fallback
+}
+
+// isHome reports whether dir is the home directory, where .hexa/ holds the cache, not a config
+func isHome(dir string) bool {
+	home, err := os.UserHomeDir()
+	return err == nil && dir == home
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
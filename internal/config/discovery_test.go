@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mkTree creates the given files and directories (trailing slash) under root
+func mkTree(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		full := filepath.Join(root, path)
+		if path[len(path)-1] == '/' {
+			if err := os.MkdirAll(full, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindProjectDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		tree  []string
+		start string // Relative to the home directory
+		want  string // Relative to the home directory
+	}{
+		{
+			name:  "project file in a parent",
+			tree:  []string{"repo/.git/", "repo/.hexa.yml", "repo/src/pkg/"},
+			start: "repo/src/pkg",
+			want:  "repo",
+		},
+		{
+			name:  "local file only",
+			tree:  []string{"repo/.git/", "repo/.hexa.local.yml", "repo/src/"},
+			start: "repo/src",
+			want:  "repo",
+		},
+		{
+			name:  "directory form",
+			tree:  []string{"repo/.git/", "repo/.hexa/config.yml", "repo/src/"},
+			start: "repo/src",
+			want:  "repo",
+		},
+		{
+			name:  "nearest wins",
+			tree:  []string{"repo/.git/", "repo/.hexa.yml", "repo/service/.hexa.yml", "repo/service/cmd/"},
+			start: "repo/service/cmd",
+			want:  "repo/service",
+		},
+		{
+			name:  "stops at the git root",
+			tree:  []string{"work/.hexa.yml", "work/repo/.git/", "work/repo/src/"},
+			start: "work/repo/src",
+			want:  "work/repo",
+		},
+		{
+			name:  "never reaches home",
+			tree:  []string{".hexa.yml", ".hexa/cache/", "notes/today/"},
+			start: "notes/today",
+			want:  "notes/today",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			mkTree(t, home, tt.tree...)
+
+			if got := FindProjectDir(filepath.Join(home, tt.start)); got != filepath.Join(home, tt.want) {
+				t.Errorf("FindProjectDir() = %s, want %s", got, filepath.Join(home, tt.want))
+			}
+		})
+	}
+}
+
+func TestProjectConfigPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		tree      []string
+		explicit  string // --config or HEXA_CONFIG, relative to the project
+		wantPath  string
+		wantLocal string
+	}{
+		{
+			name:      "project files",
+			tree:      []string{".git/", ".hexa.yml"},
+			wantPath:  ".hexa.yml",
+			wantLocal: ".hexa.local.yml",
+		},
+		{
+			name:      "directory form",
+			tree:      []string{".git/", ".hexa/"},
+			wantPath:  ".hexa/config.yml",
+			wantLocal: ".hexa/config.local.yml",
+		},
+		{
+			name:      "explicit file",
+			tree:      []string{".git/", ".hexa.yml", "ci/hexa.yml"},
+			explicit:  "ci/hexa.yml",
+			wantPath:  "ci/hexa.yml",
+			wantLocal: "ci/hexa.local.yml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			dir, err := filepath.EvalSymlinks(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			mkTree(t, dir, tt.tree...)
+			t.Chdir(dir)
+
+			SetConfigFile(tt.explicit)
+			t.Cleanup(func() { SetConfigFile("") })
+
+			path, err := ProjectConfigPath()
+			if err != nil || path != filepath.Join(dir, tt.wantPath) {
+				t.Errorf("ProjectConfigPath() = %s, %v, want %s", path, err, filepath.Join(dir, tt.wantPath))
+			}
+			localPath, err := LocalConfigPath()
+			if err != nil || localPath != filepath.Join(dir, tt.wantLocal) {
+				t.Errorf("LocalConfigPath() = %s, %v, want %s", localPath, err, filepath.Join(dir, tt.wantLocal))
+			}
+		})
+	}
+}
+
+func TestConfigFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{name: "separate value", args: []string{"jira", "--config", "ci.yml", "sprint"}, want: "ci.yml"},
+		{name: "equals", args: []string{"--config=ci.yml"}, env: "env.yml", want: "ci.yml"},
+		{name: "environment", args: []string{"jira"}, env: "env.yml", want: "env.yml"},
+		{name: "after --", args: []string{"alias", "--", "--config", "ci.yml"}, want: ""},
+		{name: "missing value", args: []string{"--config"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnv, tt.env)
+			if got := ConfigFlag(tt.args); got != tt.want {
+				t.Errorf("ConfigFlag(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
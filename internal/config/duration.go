@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ParseDuration reads a TypeDuration value: a number of seconds ("90") or a Go duration ("90s", "12h")
+func ParseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration (e.g. 90s, 12h), got %q", value)
+	}
+	return d, nil
+}
+
+// GetDuration reads a TypeDuration key of the loaded configuration (viper.GetDuration would
+// read a bare 30 as 30ns). It reports false when the key is unset or invalid.
+func GetDuration(key string) (time.Duration, bool) {
+	if !viper.IsSet(key) {
+		return 0, false
+	}
+	d, err := ParseDuration(viper.GetString(key))
+	return d, err == nil
+}
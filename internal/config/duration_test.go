@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "30", want: 30 * time.Second},
+		{value: "0", want: 0},
+		{value: "90s", want: 90 * time.Second},
+		{value: "720h", want: 720 * time.Hour},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "", wantErr: true},
+		{value: "30 days", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDuration(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, %v, want %v (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  any
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "unset", value: nil},
+		{name: "bare integer", value: 30, want: 30 * time.Second, wantOK: true},
+		{name: "string", value: "2m", want: 2 * time.Minute, wantOK: true},
+		{name: "duration", value: 45 * time.Second, want: 45 * time.Second, wantOK: true},
+		{name: "invalid", value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("timeout", tt.value)
+			t.Cleanup(func() { viper.Set("timeout", nil) })
+
+			got, ok := GetDuration("timeout")
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("GetDuration() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpandEnv replaces ${VAR} with the value of the environment variable VAR, and ${VAR:-default}
+// with default when VAR is unset or empty. $${VAR} is kept as the literal ${VAR}.
+// A missing variable without default is an error.
+func ExpandEnv(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var out strings.Builder
+	var missing []string
+	rest := value
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			out.WriteString(rest)
+			break
+		}
+
+		// $${VAR} escapes the placeholder
+		if start > 0 && rest[start-1] == '$' {
+			out.WriteString(rest[:start-1])
+			out.WriteString("${")
+			rest = rest[start+2:]
+			continue
+		}
+
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in %q", value)
+		}
+		out.WriteString(rest[:start])
+		placeholder := rest[start+2 : start+end]
+		rest = rest[start+end+1:]
+
+		name, fallback, hasDefault := strings.Cut(placeholder, ":-")
+		if !isEnvName(name) {
+			return "", fmt.Errorf("invalid placeholder ${%s}", placeholder)
+		}
+		if env := os.Getenv(name); env != "" {
+			out.WriteString(env)
+		} else if hasDefault {
+			out.WriteString(fallback)
+		} else if _, set := os.LookupEnv(name); set {
+			// Set but empty: an explicit empty value
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set (use ${%s:-} to make it optional)",
+			strings.Join(missing, ", "), missing[0])
+	}
+	return out.String(), nil
+}
+
+// isEnvName reports whether name is a valid environment variable name
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		isLetter := r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isPathKey reports whether a dotted key holds a path: the schema gives it FormatPath
+// (log.file, git.worktree_base...), in a profile or not
+func isPathKey(key string) bool {
+	schema, ok := lookupSchemaKey(key)
+	return ok && schema.Format == FormatPath
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(value string) string {
+	if value != "~" && !strings.HasPrefix(value, "~/") {
+		return value
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return value
+	}
+	return filepath.Join(home, value[1:])
+}
+
+// expandSettings expands placeholders (and ~ in path keys) in every string of nested settings,
+// in place. Errors name the offending keys.
+func expandSettings(settings map[string]any, prefix string) error {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names) // Stable error messages
+
+	var errs []error
+	for _, name := range names {
+		value := settings[name]
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if err := expandSettings(v, key); err != nil {
+				errs = append(errs, err)
+			}
+		case string:
+			expanded, err := expandValue(key, v)
+			if err != nil {
+				errs = append(errs, err)
+				delete(settings, name) // Never use the literal placeholder as a value
+				continue
+			}
+			settings[name] = expanded
+		case []any:
+			for i, item := range v {
+				if s, ok := item.(string); ok {
+					expanded, err := expandValue(key, s)
+					if err != nil {
+						errs = append(errs, err)
+						continue
+					}
+					v[i] = expanded
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expandValue expands one string value of key
+func expandValue(key, value string) (string, error) {
+	expanded, err := ExpandEnv(value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	if isPathKey(key) {
+		expanded = expandHome(expanded)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("HEXA_TEST_URL", "https://jira.example.com")
+	t.Setenv("HEXA_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "no placeholder", value: "plain $HOME", want: "plain $HOME"},
+		{name: "set", value: "${HEXA_TEST_URL}/browse", want: "https://jira.example.com/browse"},
+		{name: "default unused", value: "${HEXA_TEST_URL:-https://other}", want: "https://jira.example.com"},
+		{name: "default of unset", value: "${HEXA_TEST_UNSET:-https://jira.company.com}", want: "https://jira.company.com"},
+		{name: "default of empty", value: "${HEXA_TEST_EMPTY:-fallback}", want: "fallback"},
+		{name: "empty default", value: "a${HEXA_TEST_UNSET:-}b", want: "ab"},
+		{name: "default with colon", value: "${HEXA_TEST_UNSET:-http://localhost:8080}", want: "http://localhost:8080"},
+		{name: "set but empty", value: "[${HEXA_TEST_EMPTY}]", want: "[]"},
+		{name: "escaped", value: "$${HEXA_TEST_URL}", want: "${HEXA_TEST_URL}"},
+		{name: "several", value: "${HEXA_TEST_UNSET:-a}-${HEXA_TEST_URL}", want: "a-https://jira.example.com"},
+		{name: "missing", value: "${HEXA_TEST_UNSET}", wantErr: "HEXA_TEST_UNSET is not set"},
+		{name: "unterminated", value: "${HEXA_TEST_URL", wantErr: "unterminated placeholder"},
+		{name: "invalid name", value: "${1ABC}", wantErr: "invalid placeholder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandEnv(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExpandEnv(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandEnv(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandEnv(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPathKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"log.file", true},
+		{"LOG.FILE", true},
+		{"credentials.keyFile", true},
+		{"git.worktree_base", true},
+		{"profiles.work.log.file", true},
+		{"profile", false},
+		{"profiles.work.jira.url", false},
+		{"database.url", false},
+		{"jira.url", false},
+		{"unknown.file", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := isPathKey(tt.key); got != tt.want {
+				t.Errorf("isPathKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("HEXA_TEST_TOKEN", "abc")
+
+	settings := map[string]any{
+		"jira": map[string]any{
+			"url":   "${HEXA_TEST_UNSET:-https://jira.company.com}",
+			"token": "${HEXA_TEST_TOKEN}",
+			"host":  "${HEXA_TEST_UNSET}",
+		},
+		"log":      map[string]any{"file": "~/hexa.log"},
+		"database": "~/data", // Not a path key, whatever its name
+		"secrets":  []any{"${HEXA_TEST_UNSET:-vault}"},
+	}
+	want := map[string]any{
+		"jira": map[string]any{
+			"url":   "https://jira.company.com",
+			"token": "abc",
+		},
+		"log":      map[string]any{"file": filepath.Join(home, "hexa.log")},
+		"database": "~/data",
+		"secrets":  []any{"vault"},
+	}
+
+	err := expandSettings(settings, "")
+	if err == nil || !strings.Contains(err.Error(), "jira.host") {
+		t.Errorf("expandSettings() error = %v, want one naming jira.host", err)
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("expandSettings() = %v, want %v", settings, want)
+	}
+}
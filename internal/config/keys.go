@@ -0,0 +1,136 @@
+package config
+
+import "strings"
+
+// ValueType is the expected type of a configuration value
+type ValueType string
+
+const (
+	TypeString   ValueType = "string"
+	TypeInt      ValueType = "int"
+	TypeBool     ValueType = "bool"
+	TypeDuration ValueType = "duration" // "90s", "1h", or a number of seconds
+	TypeList     ValueType = "list"
+	TypeMap      ValueType = "map" // A section with free-form keys (e.g. aliases)
+)
+
+// Value formats, checked by Validate or applied when loading
+const (
+	FormatURL  = "url"  // An absolute http(s) URL
+	FormatPath = "path" // A file or directory path, whose leading ~ is expanded
+)
+
+// Key is a configuration key read by hexa: its schema, checked by Validate, and a short
+// description for completion
+type Key struct {
+	Name        string
+	Description string
+	Type        ValueType
+	Required    bool
+	Enum        []string // Allowed values (case-insensitive), if not empty
+	Format      string   // FormatURL, FormatPath, or empty
+	Deprecated  string   // Key replacing this one, if deprecated
+}
+
+// Keys lists the configuration keys known to hexa
+var Keys = []Key{
+	{Name: "version", Description: "Config file format version (hexa config migrate)", Type: TypeInt},
+	{Name: "jira.url", Description: "Jira base URL", Type: TypeString, Required: true, Format: FormatURL},
+	{Name: "jira.token", Description: "Jira token (or secret://jira/default)", Type: TypeString},
+	{Name: "jira.boardId", Description: "Board ID (set by hexa jira init)", Type: TypeInt},
+	{Name: "jira.boardName", Description: "Board name, resolved to jira.boardId", Type: TypeString},
+	{Name: "jira.userEmail", Description: "Your Jira email (--filter=me)", Type: TypeString},
+	{Name: "jira.accountId", Description: "Your Jira Cloud account ID (--filter=me)", Type: TypeString},
+	{Name: "jira.auth.type", Description: "pat | basic | cloud | oauth", Type: TypeString, Enum: []string{"pat", "basic", "cloud", "oauth"}},
+	{Name: "jira.auth.username", Description: "Username for basic auth", Type: TypeString},
+	{Name: "jira.auth.email", Description: "Account email for Jira Cloud", Type: TypeString},
+	{Name: "jira.oauth.clientId", Description: "OAuth client ID (hexa jira login)", Type: TypeString},
+	{Name: "jira.oauth.clientSecret", Description: "OAuth client secret (confidential clients)", Type: TypeString},
+	{Name: "jira.oauth.provider", Description: "datacenter | cloud", Type: TypeString, Enum: []string{"datacenter", "cloud"}},
+	{Name: "jira.oauth.scopes", Description: "OAuth scopes", Type: TypeList},
+	{Name: "jira.oauth.redirectUrl", Description: "OAuth callback URL", Type: TypeString, Format: FormatURL},
+	{Name: "jira.oauth.authorizeUrl", Description: "OAuth authorization endpoint", Type: TypeString, Format: FormatURL},
+	{Name: "jira.oauth.tokenUrl", Description: "OAuth token endpoint", Type: TypeString, Format: FormatURL},
+	{Name: "jira.oauth.revokeUrl", Description: "OAuth revocation endpoint", Type: TypeString, Format: FormatURL},
+	{Name: "jira.incremental.enabled", Description: "Incremental sprint refresh (default: true)", Type: TypeBool},
+	{Name: "jira.incremental.maxDelta", Description: "Updated tickets above which a full fetch is made", Type: TypeInt},
+	{Name: "jira.concurrency", Description: "Sprint pages fetched in parallel", Type: TypeInt},
+	{Name: "jira.default_project", Description: "Default Jira project key", Type: TypeString},
+	{Name: "jira.timeout", Description: "Timeout of each Jira request (default: 30s)", Type: TypeDuration},
+	{Name: "jira.host", Description: "Jira host", Type: TypeString, Deprecated: "jira.url"},
+	{Name: "cache.ttl.tickets", Description: "Sprint tickets TTL", Type: TypeDuration},
+	{Name: "cache.ttl.boards", Description: "Board IDs TTL", Type: TypeDuration},
+	{Name: "cache.ttl.sprints", Description: "Active sprint TTL", Type: TypeDuration},
+	{Name: "cache.ttl.users", Description: "User profile TTL", Type: TypeDuration},
+	{Name: "cache.ttl.transitions", Description: "Ticket transitions TTL", Type: TypeDuration},
+	{Name: "cache.ttl.statuses", Description: "Jira statuses TTL", Type: TypeDuration},
+	{Name: "cache.ttl.issues", Description: "Single tickets TTL", Type: TypeDuration},
+	{Name: "cache.snapshots.enabled", Description: "Keep sprint snapshots (--at)", Type: TypeBool},
+	{Name: "cache.snapshots.maxAge", Description: "Snapshot retention", Type: TypeDuration},
+	{Name: "cache.snapshots.maxCount", Description: "Snapshots kept per sprint", Type: TypeInt},
+	{Name: "credentials.backend", Description: "file | helper", Type: TypeString, Enum: []string{"file", "helper"}},
+	{Name: "credentials.helper", Description: "git-style credential helper command", Type: TypeString},
+	{Name: "credentials.file", Description: "Encrypted credentials file", Type: TypeString, Format: FormatPath},
+	{Name: "credentials.keyFile", Description: "Passphrase file of the encrypted credentials", Type: TypeString, Format: FormatPath},
+	{Name: "log.level", Description: "debug | info | warn | error", Type: TypeString, Enum: []string{"debug", "info", "warn", "error"}},
+	{Name: "log.file", Description: "JSON log file", Type: TypeString, Format: FormatPath},
+	{Name: "git.default_branch", Description: "Default git branch", Type: TypeString},
+	{Name: "git.worktree_base", Description: "Directory of git worktrees", Type: TypeString, Format: FormatPath},
+	{Name: "git.auto_push", Description: "Push after committing", Type: TypeBool},
+	{Name: "slack.webhook_url", Description: "Slack incoming webhook URL", Type: TypeString, Format: FormatURL},
+	{Name: "user.me", Description: "Your name, shown by hexa config", Type: TypeString},
+	{Name: "user.email", Description: "Your email", Type: TypeString, Deprecated: "jira.userEmail"},
+	{Name: "profile", Description: "Active profile (hexa profile use)", Type: TypeString},
+	{Name: "profiles", Description: "Named profiles: profiles.<name>.jira.url...", Type: TypeMap},
+	{Name: "secrets", Description: "Extra keys or sections to mask in hexa config", Type: TypeList},
+	{Name: "aliases", Description: "Command aliases", Type: TypeMap},
+	{Name: "timeout", Description: "Default --timeout", Type: TypeDuration},
+	{Name: "offline", Description: "Always run offline", Type: TypeBool},
+}
+
+// LookupKey returns the schema of a dotted key (any case), or of the free-form section
+// containing it, and whether it was found
+func LookupKey(name string) (Key, bool) {
+	name = strings.ToLower(name)
+	for _, key := range Keys {
+		lower := strings.ToLower(key.Name)
+		if lower == name || (key.Type == TypeMap && strings.HasPrefix(name, lower+".")) {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// lookupSchemaKey is LookupKey for the keys of a profile too: profiles.<name>.jira.token
+// has the schema of jira.token
+func lookupSchemaKey(name string) (Key, bool) {
+	if parts := strings.SplitN(name, ".", 3); len(parts) == 3 && strings.EqualFold(parts[0], "profiles") {
+		name = parts[2]
+	}
+	return LookupKey(name)
+}
+
+// isKnownSection reports whether name is a section (prefix) of known keys, e.g. "jira.auth"
+func isKnownSection(name string) bool {
+	prefix := strings.ToLower(name) + "."
+	for _, key := range Keys {
+		if strings.HasPrefix(strings.ToLower(key.Name), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompleteKeys returns the known keys starting with prefix, as cobra completions ("key\tdescription")
+func CompleteKeys(prefix string) []string {
+	var completions []string
+	for _, key := range Keys {
+		if key.Deprecated != "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(key.Name), strings.ToLower(prefix)) {
+			completions = append(completions, key.Name+"\t"+key.Description)
+		}
+	}
+	return completions
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/viper"
+)
+
+// secretKeyNames are the key names (last segment, any case) holding secrets
+var secretKeyNames = map[string]bool{
+	"token":        true,
+	"password":     true,
+	"passphrase":   true,
+	"secret":       true,
+	"clientsecret": true,
+	"webhook_url":  true,
+}
+
+// IsSecretKey reports whether a dotted key holds a secret: a well-known name (jira.token,
+// slack.webhook_url...) or a key listed in the `secrets` config list, or under a listed section
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	parts := strings.Split(key, ".")
+	if secretKeyNames[parts[len(parts)-1]] {
+		return true
+	}
+
+	for _, secret := range viper.GetStringSlice("secrets") {
+		secret = strings.ToLower(strings.TrimSpace(secret))
+		if secret != "" && (key == secret || strings.HasPrefix(key, secret+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSettings returns a copy of nested settings (e.g. viper.AllSettings()) with secret values masked
+func MaskSettings(settings map[string]any) map[string]any {
+	return maskSettings(settings, "")
+}
+
+func maskSettings(settings map[string]any, prefix string) map[string]any {
+	masked := make(map[string]any, len(settings))
+	for name, value := range settings {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			masked[name] = maskSettings(v, key)
+		case nil:
+			masked[name] = nil
+		default:
+			if IsSecretKey(key) {
+				masked[name] = DisplayValue(key, v)
+			} else {
+				masked[name] = v
+			}
+		}
+	}
+	return masked
+}
+
+// MaskSecret hides a secret value, keeping its first characters: "abcd…(redacted)".
+// References to a secret (secret://..., ${VAR}) are not secrets and are kept as is.
+func MaskSecret(value string) string {
+	switch {
+	case value == "":
+		return ""
+	case credentials.IsReference(value), strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}"):
+		return value
+	case len(value) < 12:
+		return "…(redacted)" // Too short to reveal anything
+	default:
+		return value[:4] + "…(redacted)"
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// setSecrets sets the `secrets` config list for the test
+func setSecrets(t *testing.T, secrets ...string) {
+	t.Helper()
+	viper.Set("secrets", secrets)
+	t.Cleanup(func() { viper.Set("secrets", nil) })
+}
+
+func TestIsSecretKey(t *testing.T) {
+	setSecrets(t, "sonar.apiKey", "vault")
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"jira.token", true},
+		{"profiles.work.jira.token", true},
+		{"jira.oauth.clientSecret", true},
+		{"JIRA.OAUTH.CLIENTSECRET", true},
+		{"slack.webhook_url", true},
+		{"jira.url", false},
+		{"jira.tokenUrl", false},
+		{"sonar.apiKey", true},
+		{"sonar.apikey", true},
+		{"sonar.url", false},
+		{"vault.role", true},
+		{"vault", true},
+		{"vaultish.role", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsSecretKey(tt.key); got != tt.want {
+				t.Errorf("IsSecretKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"short", "…(redacted)"},
+		{"abcdefghijklmnop", "abcd…(redacted)"},
+		{"secret://jira/default", "secret://jira/default"},
+		{"${JIRA_TOKEN}", "${JIRA_TOKEN}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := MaskSecret(tt.value); got != tt.want {
+				t.Errorf("MaskSecret(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSettings(t *testing.T) {
+	setSecrets(t, "vault")
+
+	settings := map[string]any{
+		"jira": map[string]any{
+			"url":   "https://jira.example.com",
+			"token": "abcdefghijklmnop",
+			"auth":  map[string]any{"password": "hunter2"},
+		},
+		"vault":   map[string]any{"role": "deployer-role-name"},
+		"profile": nil,
+	}
+	want := map[string]any{
+		"jira": map[string]any{
+			"url":   "https://jira.example.com",
+			"token": "abcd…(redacted)",
+			"auth":  map[string]any{"password": "…(redacted)"},
+		},
+		"vault":   map[string]any{"role": "depl…(redacted)"},
+		"profile": nil,
+	}
+
+	if got := MaskSettings(settings); !reflect.DeepEqual(got, want) {
+		t.Errorf("MaskSettings() = %v, want %v", got, want)
+	}
+	if token := settings["jira"].(map[string]any)["token"]; token != "abcdefghijklmnop" {
+		t.Errorf("MaskSettings() modified its argument: token = %v", token)
+	}
+}
+
+func TestDisplayValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value any
+		want  string
+	}{
+		{"plain value", "jira.boardId", 12, "12"},
+		{"secret", "jira.token", "abcdefghijklmnop", "abcd…(redacted)"},
+		{"section with a secret", "jira", map[string]any{"token": "abcdefghijklmnop", "boardId": 12}, "{boardId: 12, token: abcd…(redacted)}"},
+		{"list", "jira.oauth.scopes", []any{"read", "write"}, "[read, write]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DisplayValue(tt.key, tt.value); got != tt.want {
+				t.Errorf("DisplayValue(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
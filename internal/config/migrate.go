@@ -0,0 +1,367 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hyphaene/hexa/internal/logger"
+)
+
+// CurrentVersion is the configuration format written by this hexa. Files without a version
+// key are version 1.
+const CurrentVersion = 2
+
+// BackupDirName holds the copies of the files rewritten by hexa config migrate, under $HOME:
+// out of the project, so that a backup of .hexa.local.yml is never committed
+const BackupDirName = ".hexa/backups"
+
+// Step edits a configuration document in place and describes each change it made
+// (nothing if the file did not need it)
+type Step func(root *yaml.Node) ([]string, error)
+
+// Migration upgrades configuration files to Version
+type Migration struct {
+	Version     int
+	Description string
+	Steps       []Step
+}
+
+// Migrations is the registry of format changes, in version order. A new format appends a
+// migration and bumps CurrentVersion; steps must be no-ops on files that do not need them.
+var Migrations = []Migration{
+	{
+		Version:     2,
+		Description: "Legacy keys replaced by the ones hexa reads",
+		Steps: []Step{
+			RenameKey("jira.host", "jira.url"),
+			ChangeType("jira.url", "https:// added", addURLScheme),
+			RenameKey("user.email", "jira.userEmail"),
+			ChangeType("jira.oauth.scopes", "converted to a list", splitList),
+		},
+	},
+}
+
+// MigrationResult describes the migration of one configuration file
+type MigrationResult struct {
+	Path    string
+	From    int
+	To      int
+	Changes []string
+	Backup  string // Copy of the original file ("" in dry run or when nothing was written)
+}
+
+// UpToDate reports whether the file was already at CurrentVersion
+func (r MigrationResult) UpToDate() bool {
+	return r.From == r.To
+}
+
+// ConfigFiles returns the existing configuration files: local, project and user
+func ConfigFiles() []string {
+	var paths []string
+	for _, layer := range fileLayers {
+		if layer.path != "" && exists(layer.path) {
+			paths = append(paths, layer.path)
+		}
+	}
+	return paths
+}
+
+// MigrateFile applies the pending migrations to a configuration file, keeping comments and
+// layout, and stamps it with CurrentVersion. The original is backed up before the atomic
+// rewrite; dryRun only reports the changes. An empty file is left as is (nil result).
+func MigrateFile(path string, dryRun bool) (*MigrationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: root is not a mapping", path)
+	}
+
+	version, err := fileVersion(root)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("%s: version %d was written by a newer hexa (this one knows up to %d): upgrade hexa",
+			path, version, CurrentVersion)
+	}
+
+	result := &MigrationResult{Path: path, From: version, To: CurrentVersion}
+	if result.UpToDate() {
+		return result, nil
+	}
+
+	for _, migration := range Migrations {
+		if migration.Version <= version {
+			continue
+		}
+		for _, step := range migration.Steps {
+			changes, err := step(root)
+			if err != nil {
+				return nil, fmt.Errorf("%s: migrating to version %d: %w", path, migration.Version, err)
+			}
+			result.Changes = append(result.Changes, changes...)
+		}
+	}
+	setVersion(root, CurrentVersion)
+
+	if dryRun {
+		return result, nil
+	}
+
+	output, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling yaml: %w", err)
+	}
+	if result.Backup, err = BackupFile(path, data); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, output); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// PendingMigrations returns, for the startup check, the configuration files that migrations
+// would change (a missing version stamp alone is not worth a warning). Unreadable files are skipped.
+func PendingMigrations() []MigrationResult {
+	var pending []MigrationResult
+	for _, path := range ConfigFiles() {
+		result, err := MigrateFile(path, true)
+		if err != nil {
+			logger.Debug("Checking config version", "path", path, "error", err)
+			continue
+		}
+		if result != nil && len(result.Changes) > 0 {
+			pending = append(pending, *result)
+		}
+	}
+	return pending
+}
+
+// RenameKey moves the value of a dotted key (a leaf or a whole section) to another key, with its
+// comments. If the new key is already set, the old one is only removed.
+func RenameKey(from, to string) Step {
+	return func(root *yaml.Node) ([]string, error) {
+		keyNode, valueNode := lookupEntry(root, splitKey(from))
+		if keyNode == nil {
+			return nil, nil
+		}
+		unsetNestedField(root, splitKey(from))
+
+		if lookupNode(root, splitKey(to)) != nil {
+			return []string{fmt.Sprintf("%s removed (%s is already set)", from, to)}, nil
+		}
+		if err := attachEntry(root, splitKey(to), keyNode, valueNode); err != nil {
+			return nil, fmt.Errorf("moving %s to %s: %w", from, to, err)
+		}
+		return []string{fmt.Sprintf("%s → %s", from, to)}, nil
+	}
+}
+
+// MoveSection moves the keys of a section into another one, merging them with the keys the
+// destination already has (which win)
+func MoveSection(from, to string) Step {
+	rename := RenameKey(from, to)
+	return func(root *yaml.Node) ([]string, error) {
+		source := lookupNode(root, splitKey(from))
+		if source == nil {
+			return nil, nil
+		}
+		destination := lookupNode(root, splitKey(to))
+		if destination == nil {
+			return rename(root)
+		}
+		if source.Kind != yaml.MappingNode || destination.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("moving %s to %s: both must be sections", from, to)
+		}
+
+		var changes []string
+		for i := 0; i+1 < len(source.Content); i += 2 {
+			name := source.Content[i].Value
+			if findKey(destination, name) >= 0 {
+				changes = append(changes, fmt.Sprintf("%s.%s removed (%s.%s is already set)", from, name, to, name))
+				continue
+			}
+			destination.Content = append(destination.Content, source.Content[i], source.Content[i+1])
+			changes = append(changes, fmt.Sprintf("%s.%s → %s.%s", from, name, to, name))
+		}
+		unsetNestedField(root, splitKey(from))
+		return changes, nil
+	}
+}
+
+// ChangeType converts the value of a dotted key in place; convert reports whether it changed
+// anything, described as "key: description"
+func ChangeType(key, description string, convert func(value *yaml.Node) (bool, error)) Step {
+	return func(root *yaml.Node) ([]string, error) {
+		node := lookupNode(root, splitKey(key))
+		if node == nil {
+			return nil, nil
+		}
+		changed, err := convert(node)
+		if err != nil {
+			return nil, fmt.Errorf("converting %s: %w", key, err)
+		}
+		if !changed {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("%s: %s", key, description)}, nil
+	}
+}
+
+// addURLScheme turns a bare host (such as the former jira.host) into an https URL
+func addURLScheme(node *yaml.Node) (bool, error) {
+	if node.Kind != yaml.ScalarNode || node.Value == "" || strings.Contains(node.Value, "://") ||
+		strings.HasPrefix(node.Value, "${") {
+		return false, nil
+	}
+	node.Value = "https://" + node.Value
+	return true, nil
+}
+
+// splitList turns a string of space or comma separated items into a list
+func splitList(node *yaml.Node) (bool, error) {
+	if node.Kind != yaml.ScalarNode || node.Tag == "!!null" || strings.Contains(node.Value, "${") {
+		return false, nil
+	}
+	items := strings.FieldsFunc(node.Value, func(r rune) bool { return r == ',' || r == ' ' })
+	node.Kind = yaml.SequenceNode
+	node.Tag = "!!seq"
+	node.Value = ""
+	node.Style = yaml.FlowStyle
+	node.Content = nil
+	for _, item := range items {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+	}
+	return true, nil
+}
+
+// fileVersion returns the version key of a configuration file, 1 when it has none
+func fileVersion(root *yaml.Node) (int, error) {
+	i := findKey(root, "version")
+	if i < 0 {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(root.Content[i+1].Value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid version %q", root.Content[i+1].Value)
+	}
+	return version, nil
+}
+
+// setVersion sets the version key, added as the first key of the file
+func setVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if i := findKey(root, "version"); i >= 0 {
+		value.LineComment = root.Content[i+1].LineComment
+		root.Content[i+1] = value
+		return
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	if len(root.Content) > 0 {
+		// The comment heading the file stays at the top
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// lookupEntry returns the key and value nodes of a dotted key, or nil
+func lookupEntry(mappingNode *yaml.Node, keys []string) (*yaml.Node, *yaml.Node) {
+	parent := lookupNode(mappingNode, keys[:len(keys)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	i := findKey(parent, keys[len(keys)-1])
+	if i < 0 {
+		return nil, nil
+	}
+	return parent.Content[i], parent.Content[i+1]
+}
+
+// attachEntry adds an existing key/value pair under a dotted key, renaming the key node and
+// creating the missing parent sections
+func attachEntry(mappingNode *yaml.Node, keys []string, keyNode, valueNode *yaml.Node) error {
+	node := mappingNode
+	for _, key := range keys[:len(keys)-1] {
+		i := findKey(node, key)
+		if i < 0 {
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+			node = child
+			continue
+		}
+		if node = node.Content[i+1]; node.Kind != yaml.MappingNode {
+			return fmt.Errorf("'%s' is not a section", key)
+		}
+	}
+
+	keyNode.Value = keys[len(keys)-1]
+	node.Content = append(node.Content, keyNode, valueNode)
+	return nil
+}
+
+// BackupFile copies the original content of a configuration file to ~/.hexa/backups,
+// readable by the user only (it may hold secrets)
+func BackupFile(path string, data []byte) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	dir := filepath.Join(home, BackupDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	// myrepo_.hexa.yml.20261019-150405.<random>.bak: the project files of every repository share their names
+	pattern := fmt.Sprintf("%s_%s.%s.*.bak", filepath.Base(filepath.Dir(path)), filepath.Base(path),
+		time.Now().Format("20060102-150405"))
+	file, err := os.CreateTemp(dir, pattern) // 0600
+	if err != nil {
+		return "", fmt.Errorf("backing up %s: %w", path, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("backing up %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("backing up %s: %w", path, err)
+	}
+	return file.Name(), nil
+}
+
+// writeFileAtomic replaces path through a temporary file, so that a failure never leaves a
+// truncated configuration. The file mode is kept.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
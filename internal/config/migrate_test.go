@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		want        string
+		wantFrom    int
+		wantChanges []string
+	}{
+		{
+			name:     "legacy keys",
+			file:     "# team config\n\njira:\n    host: jira.example.com # prod\n    oauth:\n        scopes: read write\nuser:\n    email: me@example.com\n",
+			want:     "# team config\n\nversion: 2\njira:\n    oauth:\n        scopes: [read, write]\n    url: https://jira.example.com # prod\n    userEmail: me@example.com\n",
+			wantFrom: 1,
+			wantChanges: []string{
+				"jira.host → jira.url",
+				"jira.url: https:// added",
+				"user.email → jira.userEmail",
+				"jira.oauth.scopes: converted to a list",
+			},
+		},
+		{
+			name:        "new key already set",
+			file:        "jira:\n    host: old.example.com\n    url: https://jira.example.com\n",
+			want:        "version: 2\njira:\n    url: https://jira.example.com\n",
+			wantFrom:    1,
+			wantChanges: []string{"jira.host removed (jira.url is already set)"},
+		},
+		{
+			name:        "placeholders kept",
+			file:        "jira:\n    host: ${JIRA_HOST}\n    oauth:\n        scopes: ${SCOPES}\n",
+			want:        "version: 2\njira:\n    oauth:\n        scopes: ${SCOPES}\n    url: ${JIRA_HOST}\n",
+			wantFrom:    1,
+			wantChanges: []string{"jira.host → jira.url"},
+		},
+		{
+			name:     "only stamped",
+			file:     "jira:\n    url: https://jira.example.com\n",
+			want:     "version: 2\njira:\n    url: https://jira.example.com\n",
+			wantFrom: 1,
+		},
+		{
+			name:     "up to date",
+			file:     "version: 2\njira:\n    host: kept.example.com\n",
+			want:     "version: 2\njira:\n    host: kept.example.com\n",
+			wantFrom: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			path := writeFile(t, tt.file)
+
+			dry, err := MigrateFile(path, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.file {
+				t.Errorf("dry run changed the file:\n%s", got)
+			}
+
+			result, err := MigrateFile(path, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.From != tt.wantFrom || result.To != CurrentVersion {
+				t.Errorf("version %d → %d, want %d → %d", result.From, result.To, tt.wantFrom, CurrentVersion)
+			}
+			if !slices.Equal(result.Changes, tt.wantChanges) || !slices.Equal(dry.Changes, tt.wantChanges) {
+				t.Errorf("changes = %q (dry run %q), want %q", result.Changes, dry.Changes, tt.wantChanges)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Errorf("file =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			if result.UpToDate() {
+				if result.Backup != "" {
+					t.Errorf("backup %s of an up-to-date file", result.Backup)
+				}
+				return
+			}
+			backup, err := os.ReadFile(result.Backup)
+			if err != nil || string(backup) != tt.file {
+				t.Errorf("backup = %q, %v, want the original file", backup, err)
+			}
+			if info, err := os.Stat(result.Backup); err != nil || info.Mode().Perm() != 0600 {
+				t.Errorf("backup mode = %v, %v, want 0600", info.Mode().Perm(), err)
+			}
+			if home, _ := os.UserHomeDir(); !strings.HasPrefix(result.Backup, filepath.Join(home, BackupDirName)) {
+				t.Errorf("backup %s is not under ~/%s", result.Backup, BackupDirName)
+			}
+
+			again, err := MigrateFile(path, false)
+			if err != nil || !again.UpToDate() || len(again.Changes) > 0 {
+				t.Errorf("second migration = %+v, %v, want up to date", again, err)
+			}
+		})
+	}
+}
+
+func TestMigrateFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"newer version", "version: 99\n", "written by a newer hexa"},
+		{"invalid version", "version: two\n", "invalid version"},
+		{"not a mapping", "- a\n- b\n", "root is not a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.file)
+			if _, err := MigrateFile(path, false); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("MigrateFile() error = %v, want %q", err, tt.wantErr)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.file {
+				t.Errorf("file changed:\n%s", got)
+			}
+		})
+	}
+
+	if result, err := MigrateFile(writeFile(t, "# empty\n"), false); result != nil || err != nil {
+		t.Errorf("MigrateFile() of an empty file = %v, %v, want nil", result, err)
+	}
+}
+
+func TestMoveSection(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		want        string
+		wantChanges []string
+	}{
+		{
+			name:        "missing destination",
+			file:        "old:\n    a: 1\n",
+			want:        "new:\n    a: 1\n",
+			wantChanges: []string{"old → new"},
+		},
+		{
+			name:        "merged, destination wins",
+			file:        "old:\n    a: 1\n    b: 2\nnew:\n    b: 3\n",
+			want:        "new:\n    b: 3\n    a: 1\n",
+			wantChanges: []string{"old.a → new.a", "old.b removed (new.b is already set)"},
+		},
+		{
+			name: "missing source",
+			file: "new:\n    b: 3\n",
+			want: "new:\n    b: 3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.file), &doc); err != nil {
+				t.Fatal(err)
+			}
+			changes, err := MoveSection("old", "new")(doc.Content[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(changes, tt.wantChanges) {
+				t.Errorf("changes = %q, want %q", changes, tt.wantChanges)
+			}
+			if got, _ := yaml.Marshal(&doc); string(got) != tt.want {
+				t.Errorf("file =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// ProfilesKey is the section holding the named profiles: profiles.<name>.jira.url...
+	ProfilesKey = "profiles"
+	// ProfileKey selects the active profile in a config file (hexa profile use)
+	ProfileKey = "profile"
+	// ProfileEnv selects the active profile, like --profile
+	ProfileEnv = "HEXA_PROFILE"
+	// DefaultProfile is the configuration without any profile applied
+	DefaultProfile = "default"
+
+	// StateDirName holds what hexa keeps between runs (cache, outbox, OAuth tokens), under $HOME
+	StateDirName = ".hexa"
+	// ProfilesDirName holds the state of each named profile, under StateDirName
+	ProfilesDirName = "profiles"
+)
+
+// profileFlag is the profile given by --profile ("" to use HEXA_PROFILE or the profile key)
+var profileFlag string
+
+// activeProfile is the profile applied by Initialize ("" without profile)
+var activeProfile string
+
+// SetProfile selects the profile given by --profile. It must be called before Initialize.
+func SetProfile(name string) {
+	profileFlag = name
+}
+
+// ProfileFlag returns the value of --profile in args (parsed before cobra, like --config)
+func ProfileFlag(args []string) string {
+	return flagValue(args, "profile")
+}
+
+// ActiveProfile returns the name of the applied profile, "" without profile
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// Profiles returns the names of the profiles defined in the configuration, sorted.
+// Names are case-insensitive, and shown in lower case like every key.
+func Profiles() []string {
+	var names []string
+	for name := range viper.GetStringMap(ProfilesKey) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileSettings returns the settings of a profile, nil if it is not defined
+func ProfileSettings(name string) map[string]any {
+	settings, ok := viper.GetStringMap(ProfilesKey)[strings.ToLower(name)].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return settings
+}
+
+// ProfileValue returns the value of a dotted key in a profile, nil if the profile does not set it
+func ProfileValue(name, key string) any {
+	value, _ := lookupSetting(ProfileSettings(name), strings.ToLower(key))
+	return value
+}
+
+// applyProfile merges the settings of the selected profile (--profile, then HEXA_PROFILE or
+// the profile key) over the configuration files; environment variables still win
+func applyProfile() error {
+	activeProfile = ""
+	profileLayer = nil
+
+	name := profileFlag
+	if name == "" {
+		name = viper.GetString(ProfileKey) // HEXA_PROFILE included (AutomaticEnv)
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == DefaultProfile {
+		return nil
+	}
+
+	settings := ProfileSettings(name)
+	if settings == nil {
+		return fmt.Errorf("profile '%s' is not defined under %s (hexa profile list)", name, ProfilesKey)
+	}
+	profileLayer = &fileLayer{name: LayerProfile, path: ProfilesKey + "." + name, settings: copySettings(settings)}
+	if err := viper.MergeConfigMap(copySettings(settings)); err != nil {
+		return fmt.Errorf("applying profile '%s': %w", name, err)
+	}
+	activeProfile = name
+	return nil
+}
+
+// StateDir returns the directory of the data hexa keeps between runs (cache, outbox, OAuth
+// tokens): ~/.hexa, or ~/.hexa/profiles/<name> with an active profile, so that sprint IDs,
+// pending updates and logins of different Jira instances never mix
+func StateDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return profileStateDir(home, activeProfile), nil
+}
+
+// ProfileStateDir returns the state directory of a profile, see StateDir
+func ProfileStateDir(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return profileStateDir(home, strings.ToLower(name)), nil
+}
+
+func profileStateDir(home, name string) string {
+	if name == "" || name == DefaultProfile {
+		return filepath.Join(home, StateDirName)
+	}
+	return filepath.Join(home, StateDirName, ProfilesDirName, name)
+}
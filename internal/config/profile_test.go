@@ -0,0 +1,199 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// initialize writes the user, project and local files and loads them with the given profile
+func initialize(t *testing.T, user, project, local, profile string) error {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectDir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(home, ".hexa.yml"):           user,
+		filepath.Join(projectDir, ProjectFileName): project,
+		filepath.Join(projectDir, LocalFileName):   local,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	SetConfigFile(filepath.Join(projectDir, ProjectFileName))
+	SetProfile(profile)
+	t.Cleanup(func() {
+		SetConfigFile("")
+		SetProfile("")
+		viper.Reset()
+		activeProfile, profileLayer = "", nil
+	})
+	return Initialize()
+}
+
+func TestProfileLayering(t *testing.T) {
+	const user = `
+jira:
+  url: https://user.example.com
+  boardId: 1
+profiles:
+  work:
+    jira:
+      url: https://work.example.com
+      boardName: Team A
+    log:
+      level: debug
+    team:
+      channel: "#team-a"
+`
+	const project = `
+jira:
+  boardId: 2
+profile: work
+`
+
+	tests := []struct {
+		name      string
+		local     string
+		flag      string
+		env       map[string]string
+		key       string
+		want      string
+		wantLayer string
+	}{
+		{name: "profile over the files", key: "jira.url", want: "https://work.example.com", wantLayer: LayerProfile},
+		{name: "key only in the profile", key: "jira.boardName", want: "Team A", wantLayer: LayerProfile},
+		{name: "unknown key only in the profile", key: "team.channel", want: "#team-a", wantLayer: LayerProfile},
+		{name: "file key kept", key: "jira.boardId", want: "2", wantLayer: LayerProject},
+		{name: "environment over the profile", env: map[string]string{"HEXA_JIRA_URL": "https://env.example.com"},
+			key: "jira.url", want: "https://env.example.com", wantLayer: LayerEnv},
+		{name: "local file under the profile", local: "jira:\n  url: https://local.example.com\n",
+			key: "jira.url", want: "https://work.example.com", wantLayer: LayerProfile},
+		{name: "default profile", flag: DefaultProfile, key: "jira.url", want: "https://user.example.com", wantLayer: LayerUser},
+		{name: "HEXA_PROFILE", env: map[string]string{ProfileEnv: "default"},
+			key: "jira.url", want: "https://user.example.com", wantLayer: LayerUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			if err := initialize(t, user, project, tt.local, tt.flag); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := viper.GetString(tt.key); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+			}
+			sources := Explain(tt.key)
+			if len(sources) == 0 || sources[0].Layer != tt.wantLayer {
+				t.Fatalf("Explain(%s) = %v, want %s first", tt.key, sources, tt.wantLayer)
+			}
+			if !slices.Contains(ExplainableKeys(""), strings.ToLower(tt.key)) {
+				t.Errorf("ExplainableKeys() misses %s", tt.key)
+			}
+		})
+	}
+}
+
+func TestProfileStateDir(t *testing.T) {
+	const user = "profiles:\n  work:\n    jira:\n      url: https://work.example.com\n"
+
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"", StateDirName},
+		{DefaultProfile, StateDirName},
+		{"WORK", filepath.Join(StateDirName, ProfilesDirName, "work")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			if err := initialize(t, user, "", "", tt.profile); err != nil {
+				t.Fatal(err)
+			}
+			dir, err := StateDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			home, _ := os.UserHomeDir()
+			if want := filepath.Join(home, tt.want); dir != want {
+				t.Errorf("StateDir() = %s, want %s", dir, want)
+			}
+		})
+	}
+}
+
+func TestUndefinedProfile(t *testing.T) {
+	err := initialize(t, "jira:\n  url: https://user.example.com\n", "", "", "missing")
+	if err == nil || !strings.Contains(err.Error(), "profile 'missing' is not defined") {
+		t.Fatalf("Initialize() error = %v, want an undefined profile", err)
+	}
+	if ActiveProfile() != "" {
+		t.Errorf("ActiveProfile() = %q, want none", ActiveProfile())
+	}
+	if got := viper.GetString("jira.url"); got != "https://user.example.com" {
+		t.Errorf("jira.url = %q, want the file value", got)
+	}
+}
+
+func TestValidateProfileSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "profile keys",
+			content: `profiles:
+  work:
+    jira:
+      boardId: twelve
+      bordName: Team
+    user:
+      email: me@example.com
+  home:
+    jira:
+      url: https://home.example.com
+`,
+			want: []string{
+				`4:16: error: profiles.work.jira.boardId: expected an integer, got "twelve"`,
+				"5:7: error: profiles.work.jira.bordName: unknown key (did you mean jira.boardName?)",
+				"7:7: warning: profiles.work.user.email: deprecated, use jira.userEmail instead (hexa config migrate)",
+			},
+		},
+		{
+			name:    "not a section",
+			content: "profiles: work\n",
+			want:    []string{"1:11: error: profiles: expected a section of profiles"},
+		},
+		{
+			name:    "profile not a section",
+			content: "profiles:\n  work: true\n",
+			want:    []string{"2:9: error: profiles.work: expected a section of keys"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, issues := validateYAML(t, tt.content)
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, strings.TrimPrefix(issue.String(), path+":"))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateFile() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Configuration layers, from the highest precedence to the lowest
+const (
+	LayerEnv     = "env"     // HEXA_* variable of the environment
+	LayerDotenv  = ".env"    // HEXA_* variable loaded from the .env file
+	LayerProfile = "profile" // profiles.<name> section of the active profile
+	LayerLocal   = "local"   // .hexa.local.yml
+	LayerProject = "project" // .hexa.yml
+	LayerUser    = "user"    // ~/.hexa.yml
+)
+
+// EnvPrefix prefixes the environment variables overriding configuration keys
+const EnvPrefix = "HEXA"
+
+// Source is a layer defining a configuration key
+type Source struct {
+	Layer    string // One of the Layer* constants
+	Location string // File path or environment variable name
+	Value    any
+}
+
+// fileLayer is a configuration file as loaded by Initialize
+type fileLayer struct {
+	name     string
+	path     string
+	settings map[string]any
+}
+
+var (
+	fileLayers   []fileLayer     // Highest precedence first
+	profileLayer *fileLayer      // Active profile, above the files (nil without profile)
+	dotenvVars   map[string]bool // Variables set by the .env file
+)
+
+// recordLayers remembers what each layer defined, for Explain. The settings are copied:
+// viper.MergeConfigMap shares nested maps between the merged layers.
+func recordLayers(preset map[string]bool, user, project, local map[string]any) {
+	userPath, _ := UserConfigPath()
+	projectPath, _ := ProjectConfigPath()
+	localPath, _ := LocalConfigPath()
+	fileLayers = []fileLayer{
+		{LayerLocal, localPath, copySettings(local)},
+		{LayerProject, projectPath, copySettings(project)},
+		{LayerUser, userPath, copySettings(user)},
+	}
+
+	dotenvVars = map[string]bool{}
+	for name := range environNames() {
+		if !preset[name] {
+			dotenvVars[name] = true
+		}
+	}
+}
+
+// environNames returns the names of the HEXA_* environment variables
+func environNames() map[string]bool {
+	names := map[string]bool{}
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, EnvPrefix+"_") {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// EnvVarName returns the environment variable overriding key (jira.boardId -> HEXA_JIRA_BOARDID)
+func EnvVarName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Explain returns every layer defining key, in precedence order: the first one wins
+func Explain(key string) []Source {
+	key = strings.ToLower(key)
+	var sources []Source
+
+	name := EnvVarName(key)
+	if value, ok := os.LookupEnv(name); ok && value != "" { // Viper ignores empty variables
+		layer := LayerEnv
+		if dotenvVars[name] {
+			layer = LayerDotenv
+		}
+		sources = append(sources, Source{Layer: layer, Location: name, Value: value})
+	}
+
+	for _, layer := range settingLayers() {
+		if value, ok := lookupSetting(layer.settings, key); ok {
+			sources = append(sources, Source{Layer: layer.name, Location: layer.path, Value: value})
+		}
+	}
+
+	return sources
+}
+
+// settingLayers returns the active profile and the configuration files, highest precedence first
+func settingLayers() []fileLayer {
+	if profileLayer == nil {
+		return fileLayers
+	}
+	return append([]fileLayer{*profileLayer}, fileLayers...)
+}
+
+// ExplainableKeys returns, sorted, the keys set by at least one layer (under prefix if not empty):
+// the leaves of the active profile and of the configuration files, and the known keys overridden
+// by environment variables
+func ExplainableKeys(prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	set := map[string]bool{}
+	for _, layer := range settingLayers() {
+		flattenSettings(layer.settings, "", set)
+	}
+	for _, key := range Keys {
+		if len(Explain(key.Name)) > 0 {
+			set[strings.ToLower(key.Name)] = true
+		}
+	}
+
+	var keys []string
+	for key := range set {
+		if prefix == "" || key == prefix || strings.HasPrefix(key, prefix+".") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// copySettings returns a deep copy of nested settings
+func copySettings(settings map[string]any) map[string]any {
+	if settings == nil {
+		return nil
+	}
+	copied := make(map[string]any, len(settings))
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			value = copySettings(nested)
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// lookupSetting reads a dotted key in the nested (lowercased) settings of a layer
+func lookupSetting(settings map[string]any, key string) (any, bool) {
+	var current any = settings
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	if _, isSection := current.(map[string]any); isSection {
+		return nil, false
+	}
+	return current, true
+}
+
+// flattenSettings adds the dotted keys of the leaves of settings to keys
+func flattenSettings(settings map[string]any, prefix string, keys map[string]bool) {
+	for name, value := range settings {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenSettings(nested, key, keys)
+			continue
+		}
+		keys[key] = true
+	}
+}
+
+// DisplayValue formats a configuration value for display, masking secrets (those of a section too)
+func DisplayValue(key string, value any) string {
+	if section, ok := value.(map[string]any); ok {
+		return FormatValue(maskSettings(section, key))
+	}
+	if IsSecretKey(key) {
+		return MaskSecret(fmt.Sprint(value))
+	}
+	return FormatValue(value)
+}
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/viper"
+)
+
+// Severity of a validation issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found in the configuration. File is a config file path or an environment
+// variable name (empty for the merged configuration); Line and Column are 0 when unknown.
+type Issue struct {
+	File     string
+	Line     int
+	Column   int
+	Key      string
+	Severity Severity
+	Message  string
+}
+
+// String formats the issue like a compiler: "file:line:col: error: key: message"
+func (i Issue) String() string {
+	var location string
+	switch {
+	case i.File != "" && i.Line > 0:
+		location = fmt.Sprintf("%s:%d:%d: ", i.File, i.Line, i.Column)
+	case i.File != "":
+		location = i.File + ": "
+	}
+	if i.Key != "" {
+		return fmt.Sprintf("%s%s: %s: %s", location, i.Severity, i.Key, i.Message)
+	}
+	return fmt.Sprintf("%s%s: %s", location, i.Severity, i.Message)
+}
+
+// Validate checks the configuration files (user, project and local) and the HEXA_* environment
+// variables against Keys, then the merged configuration for required keys
+func Validate() []Issue {
+	var issues []Issue
+	for _, layer := range fileLayers {
+		if layer.path == "" || !exists(layer.path) {
+			continue
+		}
+		issues = append(issues, ValidateFile(layer.path)...)
+	}
+	issues = append(issues, validateEnv()...)
+	issues = append(issues, validateRequired()...)
+	return issues
+}
+
+// ValidateFile checks one configuration file, locating the issues with the YAML node positions
+func ValidateFile(path string) []Issue {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []Issue{{File: path, Severity: SeverityError, Message: err.Error()}}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Issue{{File: path, Severity: SeverityError, Message: err.Error()}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil // Empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []Issue{{File: path, Line: root.Line, Column: root.Column, Severity: SeverityError,
+			Message: "the configuration must be a mapping of keys"}}
+	}
+
+	var issues []Issue
+	validateMapping(path, root, "", &issues)
+	return issues
+}
+
+// validateMapping checks the keys of a mapping node, recursing into the known sections
+func validateMapping(path string, mapping *yaml.Node, prefix string, issues *[]Issue) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keyNode, valueNode := mapping.Content[i], mapping.Content[i+1]
+		name := keyNode.Value
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		report := func(severity Severity, format string, args ...any) {
+			*issues = append(*issues, Issue{File: path, Line: keyNode.Line, Column: keyNode.Column,
+				Key: name, Severity: severity, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if prefix == "" && strings.EqualFold(name, ProfilesKey) {
+			validateProfiles(path, valueNode, issues)
+			continue
+		}
+
+		if key, ok := LookupKey(name); ok {
+			if key.Type == TypeMap && !strings.EqualFold(key.Name, name) {
+				continue // Free-form entry of a map section (e.g. aliases.daily)
+			}
+			if key.Name != name {
+				report(SeverityWarning, "write %s (keys are case-insensitive, but other tools are not)", key.Name)
+			}
+			if key.Deprecated != "" {
+				report(SeverityWarning, "deprecated, use %s instead (hexa config migrate)", key.Deprecated)
+			}
+			if err := checkNode(key, valueNode); err != nil {
+				*issues = append(*issues, Issue{File: path, Line: valueNode.Line, Column: valueNode.Column,
+					Key: name, Severity: SeverityError, Message: err.Error()})
+			}
+			continue
+		}
+
+		if isKnownSection(name) {
+			if valueNode.Kind != yaml.MappingNode {
+				report(SeverityError, "expected a section of keys")
+				continue
+			}
+			validateMapping(path, valueNode, name, issues)
+			continue
+		}
+
+		if suggestion := suggestKey(name); suggestion != "" {
+			report(SeverityError, "unknown key (did you mean %s?)", suggestion)
+		} else {
+			report(SeverityError, "unknown key")
+		}
+	}
+}
+
+// validateProfiles checks each profile of the profiles section like a configuration file
+func validateProfiles(path string, section *yaml.Node, issues *[]Issue) {
+	if section.Kind != yaml.MappingNode {
+		*issues = append(*issues, Issue{File: path, Line: section.Line, Column: section.Column, Key: ProfilesKey,
+			Severity: SeverityError, Message: "expected a section of profiles"})
+		return
+	}
+
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		keyNode, profile := section.Content[i], section.Content[i+1]
+		prefix := ProfilesKey + "." + keyNode.Value
+		if profile.Kind != yaml.MappingNode {
+			*issues = append(*issues, Issue{File: path, Line: profile.Line, Column: profile.Column, Key: prefix,
+				Severity: SeverityError, Message: "expected a section of keys"})
+			continue
+		}
+
+		// Keys are checked as top-level ones, then reported under the profile
+		start := len(*issues)
+		validateMapping(path, profile, "", issues)
+		for j := start; j < len(*issues); j++ {
+			(*issues)[j].Key = prefix + "." + (*issues)[j].Key
+		}
+	}
+}
+
+// CheckKey checks a value typed for a key (e.g. by hexa config setup) against its schema;
+// unknown keys are accepted
+func CheckKey(name, value string) error {
+	key, ok := LookupKey(name)
+	if !ok || key.Type == TypeMap {
+		return nil
+	}
+	return checkValue(key, value)
+}
+
+// checkNode checks the YAML value of key against its schema
+func checkNode(key Key, node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch {
+	case key.Type == TypeMap:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("expected a section of keys")
+		}
+		return nil
+	case node.Kind == yaml.SequenceNode && key.Type == TypeList:
+		return nil
+	case node.Kind != yaml.ScalarNode:
+		return fmt.Errorf("expected a %s, got a %s", key.Type, kindName(node.Kind))
+	case node.Tag == "!!null":
+		return nil
+	}
+	return checkValue(key, node.Value)
+}
+
+// checkValue checks a scalar value of key against its schema. Placeholders and secret
+// references are only known once expanded: they are accepted as is.
+func checkValue(key Key, value string) error {
+	if strings.Contains(value, "${") || credentials.IsReference(value) {
+		return nil
+	}
+
+	switch key.Type {
+	case TypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+	case TypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+	case TypeDuration:
+		if _, err := ParseDuration(value); err != nil {
+			return err
+		}
+	}
+
+	if len(key.Enum) > 0 && value != "" {
+		valid := false
+		for _, allowed := range key.Enum {
+			valid = valid || strings.EqualFold(value, allowed)
+		}
+		if !valid {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(key.Enum, ", "))
+		}
+	}
+
+	if key.Format == FormatURL && value != "" {
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%q is not an absolute http(s) URL (e.g. https://jira.example.com)", value)
+		}
+	}
+	return nil
+}
+
+// validateEnv checks the values of the HEXA_* variables overriding known keys
+func validateEnv() []Issue {
+	var issues []Issue
+	for _, key := range Keys {
+		if key.Type == TypeMap {
+			continue
+		}
+		name := EnvVarName(key.Name)
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if err := checkValue(key, value); err != nil {
+			issues = append(issues, Issue{File: name, Key: key.Name, Severity: SeverityError, Message: err.Error()})
+		}
+	}
+	return issues
+}
+
+// validateRequired checks the merged configuration for the required keys. jira.token is
+// required unless hexa authenticates with OAuth (hexa jira login).
+func validateRequired() []Issue {
+	var issues []Issue
+	for _, key := range Keys {
+		if key.Required && viper.GetString(key.Name) == "" {
+			issues = append(issues, Issue{Key: key.Name, Severity: SeverityError,
+				Message: fmt.Sprintf("required (set it in .hexa.yml or %s)", EnvVarName(key.Name))})
+		}
+	}
+	if viper.GetString("jira.token") == "" && !strings.EqualFold(viper.GetString("jira.auth.type"), "oauth") {
+		issues = append(issues, Issue{Key: "jira.token", Severity: SeverityError,
+			Message: fmt.Sprintf("required unless jira.auth.type is oauth (set it in .hexa.local.yml, %s or hexa auth set)", EnvVarName("jira.token"))})
+	}
+	return issues
+}
+
+// UnknownKeys returns a warning for each unknown key of the loaded configuration files: a cheap
+// startup check on the settings already read, without positions (see Validate)
+func UnknownKeys() []Issue {
+	var issues []Issue
+	for _, layer := range fileLayers {
+		keys := map[string]bool{}
+		flattenSettings(layer.settings, "", keys)
+
+		var unknown []string
+		for key := range keys {
+			if _, ok := LookupKey(key); !ok && !isKnownSection(key) {
+				unknown = append(unknown, key)
+			}
+		}
+		sort.Strings(unknown)
+
+		for _, key := range unknown {
+			message := "unknown key"
+			if suggestion := suggestKey(key); suggestion != "" {
+				message = fmt.Sprintf("unknown key (did you mean %s?)", suggestion)
+			}
+			issues = append(issues, Issue{File: layer.path, Key: key, Severity: SeverityWarning, Message: message})
+		}
+	}
+	return issues
+}
+
+// suggestKey returns the known key or section closest to an unknown key, or "" if none is close
+func suggestKey(name string) string {
+	candidates := map[string]bool{}
+	for _, key := range Keys {
+		if key.Deprecated != "" {
+			continue
+		}
+		parts := strings.Split(key.Name, ".")
+		for i := 1; i <= len(parts); i++ {
+			candidates[strings.Join(parts[:i], ".")] = true
+		}
+	}
+
+	lower := strings.ToLower(name)
+	maxDistance := min(2, len(lower)/4) // Beyond that, a suggestion is noise
+	best, bestDistance := "", maxDistance+1
+	for candidate := range candidates {
+		distance := levenshtein(lower, strings.ToLower(candidate))
+		if distance < bestDistance || (distance == bestDistance && best != "" && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// kindName names a YAML node kind for error messages
+func kindName(kind yaml.Kind) string {
+	switch kind {
+	case yaml.MappingNode:
+		return "section"
+	case yaml.SequenceNode:
+		return "list"
+	default:
+		return "value"
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validateYAML writes content to a config file and validates it
+func validateYAML(t *testing.T, content string) (string, []Issue) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, ValidateFile(path)
+}
+
+func TestValidateFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // Issues formatted without the file path, in order
+	}{
+		{
+			name: "valid",
+			content: `jira:
+  url: https://jira.example.com
+  boardId: 12
+  timeout: 30
+timeout: 2m
+cache:
+  ttl:
+    tickets: ${HEXA_TTL}
+aliases:
+  daily: jira sprint fetch
+`,
+		},
+		{
+			name: "wrong types",
+			content: `jira:
+  boardId: twelve
+  url: jira.example.com
+  timeout: soon
+log:
+  level: loud
+`,
+			want: []string{
+				`2:12: error: jira.boardId: expected an integer, got "twelve"`,
+				`3:8: error: jira.url: "jira.example.com" is not an absolute http(s) URL (e.g. https://jira.example.com)`,
+				`4:12: error: jira.timeout: expected a duration (e.g. 90s, 12h), got "soon"`,
+				`6:10: error: log.level: "loud" is not one of debug, info, warn, error`,
+			},
+		},
+		{
+			name: "unknown keys",
+			content: `jira:
+  boardid: 12
+  bordName: Team
+  colour: blue
+`,
+			want: []string{
+				"2:3: warning: jira.boardid: write jira.boardId (keys are case-insensitive, but other tools are not)",
+				"3:3: error: jira.bordName: unknown key (did you mean jira.boardName?)",
+				"4:3: error: jira.colour: unknown key",
+			},
+		},
+		{
+			name: "deprecated keys",
+			content: `jira:
+  host: https://jira.example.com
+user:
+  email: me@example.com
+`,
+			want: []string{
+				"2:3: warning: jira.host: deprecated, use jira.url instead (hexa config migrate)",
+				"4:3: warning: user.email: deprecated, use jira.userEmail instead (hexa config migrate)",
+			},
+		},
+		{
+			name:    "section expected",
+			content: "jira: https://jira.example.com\n",
+			want:    []string{"1:1: error: jira: expected a section of keys"},
+		},
+		{
+			name:    "not a mapping",
+			content: "- jira\n",
+			want:    []string{"1:1: error: the configuration must be a mapping of keys"},
+		},
+		{
+			name: "empty file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, issues := validateYAML(t, tt.content)
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, strings.TrimPrefix(issue.String(), path+":"))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateFile() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	tests := []struct {
+		issue Issue
+		want  string
+	}{
+		{Issue{File: ".hexa.yml", Line: 3, Column: 5, Key: "jira.url", Severity: SeverityError, Message: "bad"}, ".hexa.yml:3:5: error: jira.url: bad"},
+		{Issue{File: "HEXA_JIRA_URL", Key: "jira.url", Severity: SeverityError, Message: "bad"}, "HEXA_JIRA_URL: error: jira.url: bad"},
+		{Issue{Key: "jira.token", Severity: SeverityError, Message: "required"}, "error: jira.token: required"},
+		{Issue{File: ".hexa.yml", Severity: SeverityWarning, Message: "unreadable"}, ".hexa.yml: warning: unreadable"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.issue.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSuggestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "jira.bordName", want: "jira.boardName"},
+		{name: "JIRA.URL", want: "jira.url"},
+		{name: "jirra", want: "jira"},
+		{name: "user.emial", want: ""}, // Deprecated keys are never suggested
+		{name: "colour", want: ""},
+		{name: "ab", want: ""}, // Too short for a suggestion
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestKey(tt.name); got != tt.want {
+				t.Errorf("suggestKey(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	t.Setenv("HEXA_JIRA_BOARDID", "twelve")
+	t.Setenv("HEXA_TIMEOUT", "90")
+	t.Setenv("HEXA_LOG_LEVEL", "${LEVEL}")
+	t.Setenv("HEXA_JIRA_URL", "ftp://jira.example.com")
+
+	var got []string
+	for _, issue := range validateEnv() {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`HEXA_JIRA_URL: error: jira.url: "ftp://jira.example.com" is not an absolute http(s) URL (e.g. https://jira.example.com)`,
+		`HEXA_JIRA_BOARDID: error: jira.boardId: expected an integer, got "twelve"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("validateEnv() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
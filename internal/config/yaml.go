@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -48,6 +52,46 @@ func UpdateYAMLField(filePath string, key string, value any) error {
 	return os.WriteFile(filePath, output, 0644)
 }
 
+// UpdateYAMLFields met à jour plusieurs champs en une seule écriture atomique, via un fichier
+// temporaire créé avec les droits perm: un fichier qui reçoit un token n'est jamais lisible par
+// d'autres, même un instant (le fichier existant prend aussi les droits perm)
+func UpdateYAMLFields(filePath string, fields []Field, perm os.FileMode) error {
+	document := &yaml.Node{}
+	if _, err := os.Stat(filePath); err == nil {
+		if document, err = readYAMLFile(filePath); err != nil {
+			return err
+		}
+	}
+	if _, err := rootMapping(document); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if err := setNestedField(document, field.Key, field.Value); err != nil {
+			return fmt.Errorf("setting field %s: %w", field.Key, err)
+		}
+	}
+
+	output, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("marshaling yaml: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp") // 0600
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	_, err = tmp.Write(output)
+	err = errors.Join(err, tmp.Chmod(perm), tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), filePath)
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("writing file: %w", err), os.Remove(tmp.Name()))
+	}
+	return nil
+}
+
 // setNestedField gère la notation pointée "parent.child" et crée les nœuds manquants
 func setNestedField(root *yaml.Node, key string, value any) error {
 	// Trouver le mapping root (premier élément de DocumentNode)
@@ -95,30 +139,28 @@ func setNestedFieldRecursive(mappingNode *yaml.Node, keys []string, value any) e
 	isLastKey := len(keys) == 1
 
 	// Chercher la clé dans le mapping actuel
-	for i := 0; i < len(mappingNode.Content); i += 2 {
-		keyNode := mappingNode.Content[i]
+	if i := findKey(mappingNode, currentKey); i >= 0 {
 		valueNode := mappingNode.Content[i+1]
 
-		if keyNode.Value == currentKey {
-			if isLastKey {
-				// Dernière clé: remplacer la valeur
-				newValueNode := &yaml.Node{}
-
-				if err := newValueNode.Encode(value); err != nil {
-					return fmt.Errorf("encoding value: %w", err)
-				}
-				mappingNode.Content[i+1] = newValueNode
-				return nil
-			} else {
-				// Clé intermédiaire: descendre dans le mapping
-				if valueNode.Kind != yaml.MappingNode {
-					// Transformer en mapping si ce n'en est pas un
-					valueNode.Kind = yaml.MappingNode
-					valueNode.Content = []*yaml.Node{}
-				}
-				return setNestedFieldRecursive(valueNode, keys[1:], value)
+		if isLastKey {
+			// Dernière clé: remplacer la valeur
+			newValueNode := &yaml.Node{}
+
+			if err := newValueNode.Encode(value); err != nil {
+				return fmt.Errorf("encoding value: %w", err)
 			}
+			newValueNode.LineComment = valueNode.LineComment // Garder "# commentaire" en fin de ligne
+			mappingNode.Content[i+1] = newValueNode
+			return nil
+		}
+
+		// Clé intermédiaire: descendre dans le mapping
+		if valueNode.Kind != yaml.MappingNode {
+			// Transformer en mapping si ce n'en est pas un
+			valueNode.Kind = yaml.MappingNode
+			valueNode.Content = []*yaml.Node{}
 		}
+		return setNestedFieldRecursive(valueNode, keys[1:], value)
 	}
 
 	// Clé non trouvée: créer
@@ -150,21 +192,256 @@ func setNestedFieldRecursive(mappingNode *yaml.Node, keys []string, value any) e
 }
 
 // ReadYAMLField lit un champ depuis un fichier YAML
+// Supporte les chemins imbriqués avec notation pointée: "jira.boardId"
 func ReadYAMLField(filePath string, key string) (any, error) {
+	root, err := readYAMLDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	node := lookupNode(root, splitKey(key))
+	if node == nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrKeyNotFound, key)
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decoding '%s': %w", key, err)
+	}
+	return value, nil
+}
+
+// UnsetYAMLField supprime un champ (et les parents devenus vides) en préservant le reste du fichier,
+// commentaires d'en-tête compris
+func UnsetYAMLField(filePath string, key string) error {
+	document, err := readYAMLFile(filePath)
+	if err != nil {
+		return err
+	}
+	root, err := rootMapping(document)
+	if err != nil {
+		return err
+	}
+
+	if !unsetNestedField(root, splitKey(key)) {
+		return fmt.Errorf("%w: '%s'", ErrKeyNotFound, key)
+	}
+
+	var output []byte
+	switch {
+	case len(root.Content) > 0:
+		if output, err = yaml.Marshal(document); err != nil {
+			return fmt.Errorf("marshaling yaml: %w", err)
+		}
+	case document.HeadComment != "":
+		output = []byte(document.HeadComment + "\n") // Pas de "{}" dans un fichier vidé
+	}
+
+	return os.WriteFile(filePath, output, 0644)
+}
+
+// unsetNestedField retire la clé du mapping; renvoie false si elle n'existe pas
+func unsetNestedField(mappingNode *yaml.Node, keys []string) bool {
+	if mappingNode == nil || mappingNode.Kind != yaml.MappingNode || len(keys) == 0 {
+		return false
+	}
+
+	i := findKey(mappingNode, keys[0])
+	if i < 0 {
+		return false
+	}
+
+	if len(keys) > 1 {
+		child := mappingNode.Content[i+1]
+		if !unsetNestedField(child, keys[1:]) {
+			return false
+		}
+		if len(child.Content) > 0 {
+			return true
+		}
+		// Parent vide: le supprimer aussi
+	}
+
+	mappingNode.Content = append(mappingNode.Content[:i], mappingNode.Content[i+2:]...)
+	return true
+}
+
+// Field est une feuille d'un fichier YAML, avec sa clé pointée
+type Field struct {
+	Key   string
+	Value any
+}
+
+// ListYAMLFields renvoie toutes les feuilles du fichier ("jira.boardId", ...) dans l'ordre du fichier.
+// Les listes sont des feuilles.
+func ListYAMLFields(filePath string) ([]Field, error) {
+	root, err := readYAMLDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []Field
+	if err := flattenNode(root, "", &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// flattenNode ajoute les feuilles de node à fields, préfixées par prefix
+func flattenNode(node *yaml.Node, prefix string, fields *[]Field) error {
+	if node.Kind == yaml.MappingNode && (len(node.Content) > 0 || prefix == "") {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if err := flattenNode(node.Content[i+1], key, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return fmt.Errorf("decoding '%s': %w", prefix, err)
+	}
+	*fields = append(*fields, Field{Key: prefix, Value: value})
+	return nil
+}
+
+// ErrKeyNotFound est renvoyée quand la clé demandée n'existe pas dans le fichier
+var ErrKeyNotFound = errors.New("key not found")
+
+// readYAMLDocument lit le mapping racine d'un fichier YAML (vide si le fichier est vide)
+func readYAMLDocument(filePath string) (*yaml.Node, error) {
+	document, err := readYAMLFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return rootMapping(document)
+}
+
+// readYAMLFile lit le document d'un fichier YAML, avec ses commentaires d'en-tête
+func readYAMLFile(filePath string) (*yaml.Node, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
 
-	var config map[string]any
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
 		return nil, fmt.Errorf("parsing yaml: %w", err)
 	}
+	return &document, nil
+}
 
-	value, ok := config[key]
-	if !ok {
-		return nil, fmt.Errorf("key '%s' not found", key)
+// rootMapping renvoie le mapping racine du document, créé s'il est vide
+func rootMapping(document *yaml.Node) (*yaml.Node, error) {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		document.Kind = yaml.DocumentNode
+		document.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+	if document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("root is not a mapping")
 	}
+	return document.Content[0], nil
+}
 
-	return value, nil
+// lookupNode renvoie le nœud de valeur de la clé pointée, ou nil
+func lookupNode(mappingNode *yaml.Node, keys []string) *yaml.Node {
+	node := mappingNode
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		i := findKey(node, key)
+		if i < 0 {
+			return nil
+		}
+		node = node.Content[i+1]
+	}
+	return node
+}
+
+// findKey renvoie l'index de la clé dans un mapping, ou -1. Une clé exacte est préférée,
+// sinon la casse est ignorée comme le fait Viper (jira.boardid == jira.boardId).
+func findKey(mappingNode *yaml.Node, key string) int {
+	match := -1
+	for i := 0; i+1 < len(mappingNode.Content); i += 2 {
+		switch name := mappingNode.Content[i].Value; {
+		case name == key:
+			return i
+		case match < 0 && strings.EqualFold(name, key):
+			match = i
+		}
+	}
+	return match
+}
+
+// ParseKeyValue déduit le type d'une valeur saisie pour key: les clés que le schéma type
+// en chaîne (jira.token, user.me...) la gardent telle quelle, "123456" compris, les autres
+// passent par ParseValue. Les clés d'un profil (profiles.<nom>.jira.token) suivent le schéma.
+func ParseKeyValue(key, raw string) (any, error) {
+	if schema, ok := lookupSchemaKey(key); ok && schema.Type == TypeString {
+		return raw, nil
+	}
+	return ParseValue(raw)
+}
+
+// ParseValue déduit le type d'une valeur saisie en ligne de commande:
+// "true"/"false" -> bool, "42" -> int, "[a, b]" -> liste, sinon chaîne.
+// "0042" ou "1e3" restent des chaînes pour ne pas altérer un identifiant.
+func ParseValue(raw string) (any, error) {
+	switch raw {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	if n, err := strconv.Atoi(raw); err == nil && strconv.Itoa(n) == raw {
+		return n, nil
+	}
+
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		var list []any
+		if err := yaml.Unmarshal([]byte(raw), &list); err != nil {
+			return nil, fmt.Errorf("invalid list %s: %w", raw, err)
+		}
+		return list, nil
+	}
+
+	return raw, nil
+}
+
+// FormatValue affiche une valeur lue avec ReadYAMLField: chaînes telles quelles, le reste en YAML compact
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	setFlowStyle(node)
+	output, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// setFlowStyle affiche listes et mappings sur une ligne: [a, b], {x: 1}
+func setFlowStyle(node *yaml.Node) {
+	if node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode {
+		node.Style = yaml.FlowStyle
+	}
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
 }
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFile writes content to a temporary YAML file and returns its path
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".hexa.yml")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestUpdateYAMLField(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		key   string
+		value any
+		want  string
+	}{
+		{
+			name:  "missing file",
+			key:   "jira.boardId",
+			value: 12,
+			want:  "jira:\n    boardId: 12\n",
+		},
+		{
+			name:  "keeps comments",
+			file:  "# hexa\n\njira:\n    url: https://jira.example.com # prod\n    boardId: 1 # board\n",
+			key:   "jira.boardId",
+			value: 12,
+			want:  "# hexa\n\njira:\n    url: https://jira.example.com # prod\n    boardId: 12 # board\n",
+		},
+		{
+			name:  "case-insensitive key",
+			file:  "jira:\n    boardId: 1\n",
+			key:   "jira.boardid",
+			value: 2,
+			want:  "jira:\n    boardId: 2\n",
+		},
+		{
+			name:  "new section",
+			file:  "log:\n    level: warn\n",
+			key:   "jira.auth.type",
+			value: "oauth",
+			want:  "log:\n    level: warn\njira:\n    auth:\n        type: oauth\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.file)
+			if err := UpdateYAMLField(path, tt.key, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Errorf("file =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsetYAMLField(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		key     string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "keeps head comment",
+			file: "# hexa user config\n\njira:\n    url: https://jira.example.com\n    boardId: 1\n",
+			key:  "jira.boardId",
+			want: "# hexa user config\n\njira:\n    url: https://jira.example.com\n",
+		},
+		{
+			name: "removes empty parents",
+			file: "jira:\n    auth:\n        type: pat\nlog:\n    level: warn\n",
+			key:  "jira.auth.type",
+			want: "log:\n    level: warn\n",
+		},
+		{
+			name: "last key keeps the comment",
+			file: "# hexa\n\nprofile: work\n",
+			key:  "profile",
+			want: "# hexa\n",
+		},
+		{
+			name: "last key",
+			file: "profile: work\n",
+			key:  "profile",
+			want: "",
+		},
+		{
+			name:    "missing key",
+			file:    "jira:\n    url: https://jira.example.com\n",
+			key:     "jira.boardId",
+			want:    "jira:\n    url: https://jira.example.com\n",
+			wantErr: ErrKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.file)
+			if err := UnsetYAMLField(path, tt.key); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnsetYAMLField() error = %v, want %v", err, tt.wantErr)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Errorf("file =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyValue(t *testing.T) {
+	tests := []struct {
+		key  string
+		raw  string
+		want any
+	}{
+		{"jira.boardId", "42", 42},
+		{"jira.boardId", "0042", "0042"},
+		{"jira.token", "123456", "123456"},
+		{"profiles.work.jira.token", "123456", "123456"},
+		{"profiles.work.jira.boardId", "7", 7},
+		{"git.auto_push", "true", true},
+		{"jira.oauth.scopes", "[read, write]", []any{"read", "write"}},
+		{"unknown.key", "12", 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.raw, func(t *testing.T) {
+			got, err := ParseKeyValue(tt.key, tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKeyValue(%q, %q) = %#v, want %#v", tt.key, tt.raw, got, tt.want)
+			}
+		})
+	}
+}
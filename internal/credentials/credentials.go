@@ -0,0 +1,187 @@
+// Package credentials stores secrets outside of configuration files. Config values
+// such as `token: secret://jira/default` are resolved from the configured store:
+// an encrypted file (default) or an external credential helper (git-credential style).
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Scheme prefixes config values that reference a stored secret
+const Scheme = "secret://"
+
+// Store backends accepted by credentials.backend
+const (
+	BackendFile   = "file"   // Encrypted file, see FileStore
+	BackendHelper = "helper" // External command, see HelperStore
+)
+
+// ErrNotFound is returned when no secret is stored under a name
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps secrets by name (e.g. "jira/default")
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, secret string) error
+	Delete(name string) error
+}
+
+var (
+	mu       sync.Mutex
+	store    Store
+	resolved = map[string]string{} // Secrets already read by this process
+)
+
+// Default returns the store configured by credentials.backend (helper when
+// credentials.helper is set, file otherwise). It is opened once per process.
+func Default() (Store, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return defaultStore()
+}
+
+// defaultStore opens the configured store (mu must be held)
+func defaultStore() (Store, error) {
+	if store != nil {
+		return store, nil
+	}
+
+	backend := strings.ToLower(viper.GetString("credentials.backend"))
+	if backend == "" {
+		backend = BackendFile
+		if viper.GetString("credentials.helper") != "" {
+			backend = BackendHelper
+		}
+	}
+
+	switch backend {
+	case BackendFile:
+		fileStore, err := NewFileStore(viper.GetString("credentials.file"))
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	case BackendHelper:
+		helper := viper.GetString("credentials.helper")
+		if helper == "" {
+			return nil, fmt.Errorf("credentials.helper must be configured when credentials.backend is %s", BackendHelper)
+		}
+		store = NewHelperStore(helper)
+	default:
+		return nil, fmt.Errorf("invalid credentials.backend '%s' (valid: %s, %s)", backend, BackendFile, BackendHelper)
+	}
+
+	return store, nil
+}
+
+// IsReference reports whether a config value references a stored secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// Name returns the secret name of a reference ("secret://jira/default" -> "jira/default").
+// Values without the scheme are returned as is.
+func Name(value string) string {
+	return strings.TrimPrefix(value, Scheme)
+}
+
+// Resolve returns the secret referenced by value, or value itself if it is not a reference
+func Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+
+	name := Name(value)
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if secret, ok := resolved[name]; ok {
+		return secret, nil
+	}
+
+	s, err := defaultStore()
+	if err != nil {
+		return "", err
+	}
+	secret, err := s.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return "", fmt.Errorf("%w: %s (store it with 'hexa auth set %s')", ErrNotFound, value, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", value, err)
+	}
+
+	resolved[name] = secret
+	return secret, nil
+}
+
+// GetString returns the config value of key, resolving secret:// references
+func GetString(key string) (string, error) {
+	value, err := Resolve(viper.GetString(key))
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// validateName checks a secret name: "<service>/<account>", e.g. "jira/default"
+func validateName(name string) error {
+	service, account, ok := strings.Cut(name, "/")
+	if !ok || service == "" || account == "" || strings.ContainsAny(name, "\n\r=") {
+		return fmt.Errorf("invalid secret name '%s': expected <service>/<account>, e.g. jira/default", name)
+	}
+	return nil
+}
+
+// forget drops a secret from the resolved secrets of this process (after Set or Delete)
+func forget(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(resolved, name)
+}
+
+// Set stores a secret in the default store
+func Set(name, secret string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	s, err := Default()
+	if err != nil {
+		return err
+	}
+	defer forget(name)
+	return s.Set(name, secret)
+}
+
+// Get reads a secret from the default store
+func Get(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	s, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return s.Get(name)
+}
+
+// Delete removes a secret from the default store
+func Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	s, err := Default()
+	if err != nil {
+		return err
+	}
+	defer forget(name)
+	return s.Delete(name)
+}
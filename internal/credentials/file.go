@@ -0,0 +1,240 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultFileName is the encrypted store, relative to $HOME, when credentials.file is not set
+const DefaultFileName = ".hexa/credentials.enc"
+
+// PassphraseEnv unlocks the encrypted store without prompting
+const PassphraseEnv = "HEXA_CREDENTIALS_PASSPHRASE"
+
+// Key derivation parameters (PBKDF2-HMAC-SHA256, AES-256-GCM)
+const (
+	kdfName       = "pbkdf2-sha256"
+	kdfIterations = 600000
+	saltSize      = 16
+	keySize       = 32
+)
+
+// envelope is the on-disk format of the encrypted store
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"` // AES-GCM encrypted JSON object: name -> secret
+}
+
+// FileStore keeps secrets in a file encrypted with a key derived from a passphrase,
+// read from HEXA_CREDENTIALS_PASSPHRASE, the credentials.keyFile file or a prompt.
+// The file is decrypted once, on first use.
+type FileStore struct {
+	path string
+
+	mu      sync.Mutex
+	key     []byte
+	salt    []byte
+	secrets map[string]string // nil until unlocked
+}
+
+// NewFileStore returns the encrypted store at path (DefaultFileName if empty, ~/ expanded)
+func NewFileStore(path string) (*FileStore, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("getting home directory: %w", err)
+	}
+	switch {
+	case path == "":
+		path = filepath.Join(home, DefaultFileName)
+	case strings.HasPrefix(path, "~/"):
+		path = filepath.Join(home, path[2:])
+	}
+	return &FileStore{path: path}, nil
+}
+
+// Path returns the location of the encrypted file
+func (s *FileStore) Path() string {
+	return s.path
+}
+
+// Get returns the secret stored under name
+func (s *FileStore) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.unlock(false); err != nil {
+		return "", err
+	}
+	secret, ok := s.secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set stores a secret, creating the file (and asking for a new passphrase) if needed
+func (s *FileStore) Set(name, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.unlock(true); err != nil {
+		return err
+	}
+	s.secrets[name] = secret
+	return s.save()
+}
+
+// Delete removes a secret
+func (s *FileStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.unlock(false); err != nil {
+		return err
+	}
+	if _, ok := s.secrets[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.secrets, name)
+	return s.save()
+}
+
+// unlock reads and decrypts the file. A missing file is an empty store; create
+// asks for the passphrase of the new file right away (even after a Get on the empty store).
+func (s *FileStore) unlock(create bool) error {
+	if s.secrets != nil && (s.key != nil || !create) {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		if !create {
+			s.secrets = map[string]string{}
+			return nil
+		}
+		s.salt = make([]byte, saltSize)
+		if _, err := rand.Read(s.salt); err != nil {
+			return fmt.Errorf("generating salt: %w", err)
+		}
+		passphrase, err := readPassphrase(fmt.Sprintf("New passphrase for %s: ", s.path), true)
+		if err != nil {
+			return err
+		}
+		if s.key, err = deriveKey(passphrase, s.salt, kdfIterations); err != nil {
+			return err
+		}
+		s.secrets = map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return fmt.Errorf("decoding credentials file %s: %w", s.path, err)
+	}
+	if env.KDF != kdfName {
+		return fmt.Errorf("unsupported key derivation '%s' in %s", env.KDF, s.path)
+	}
+
+	passphrase, err := readPassphrase(fmt.Sprintf("Passphrase for %s: ", s.path), false)
+	if err != nil {
+		return err
+	}
+	key, err := deriveKey(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	plain, err := gcm.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt %s: wrong passphrase or corrupted file", s.path)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("decoding credentials: %w", err)
+	}
+
+	s.key, s.salt, s.secrets = key, env.Salt, secrets
+	return nil
+}
+
+// save encrypts the secrets with a fresh nonce and writes the file atomically (0600)
+func (s *FileStore) save() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return fmt.Errorf("encoding credentials: %w", err)
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+
+	data, err := json.MarshalIndent(envelope{
+		Version:    1,
+		KDF:        kdfName,
+		Iterations: kdfIterations,
+		Salt:       s.salt,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding credentials file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("creating credentials directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing credentials file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing credentials file: %w", err)
+	}
+	return nil
+}
+
+// deriveKey derives the AES-256 key from the passphrase
+func deriveKey(passphrase string, salt []byte, iterations int) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	return key, nil
+}
+
+// newGCM returns the AES-GCM cipher of key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,134 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	t.Setenv(PassphraseEnv, "correct horse")
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("jira/default"); err != ErrNotFound {
+		t.Fatalf("Get() on a missing file error = %v, want ErrNotFound", err)
+	}
+	for name, secret := range map[string]string{"jira/default": "s3cret-token", "jira/work": "other"} {
+		if err := store.Set(name, secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("mode = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "s3cret-token") || !strings.Contains(string(data), kdfName) {
+		t.Errorf("file is not an encrypted envelope:\n%s", data)
+	}
+
+	// A new store decrypts the file with the same passphrase
+	reopened, _ := NewFileStore(path)
+	if secret, err := reopened.Get("jira/default"); err != nil || secret != "s3cret-token" {
+		t.Errorf("Get() = %q, %v, want the stored secret", secret, err)
+	}
+	if err := reopened.Delete("jira/work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Delete("jira/work"); err != ErrNotFound {
+		t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+	}
+
+	again, _ := NewFileStore(path)
+	if _, err := again.Get("jira/work"); err != ErrNotFound {
+		t.Errorf("Get() of a deleted secret error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	t.Setenv(PassphraseEnv, "correct horse")
+	store, _ := NewFileStore(path)
+	if err := store.Set("jira/default", "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(PassphraseEnv, "wrong horse")
+	reopened, _ := NewFileStore(path)
+	_, err := reopened.Get("jira/default")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Get() error = %v, want a wrong passphrase error", err)
+	}
+}
+
+func TestNewFileStorePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := map[string]string{
+		"":                   filepath.Join(home, DefaultFileName),
+		"~/secrets/hexa.enc": filepath.Join(home, "secrets/hexa.enc"),
+		"/etc/hexa/hexa.enc": "/etc/hexa/hexa.enc",
+		"relative/hexa.enc":  "relative/hexa.enc",
+	}
+	for path, want := range tests {
+		store, err := NewFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if store.Path() != want {
+			t.Errorf("NewFileStore(%q).Path() = %q, want %q", path, store.Path(), want)
+		}
+	}
+}
+
+func TestReadPassphrase(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, "hexa.key"), []byte("from key file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     string
+		keyFile string
+		want    string
+		wantErr string
+	}{
+		{name: "environment first", env: "from env", keyFile: "~/hexa.key", want: "from env"},
+		{name: "then key file", keyFile: "~/hexa.key", want: "from key file"},
+		{name: "missing key file", keyFile: "~/missing.key", wantErr: "credentials.keyFile"},
+		{name: "then prompt, only in a terminal", wantErr: PassphraseEnv},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PassphraseEnv, tt.env)
+			viper.Set("credentials.keyFile", tt.keyFile)
+			t.Cleanup(func() { viper.Set("credentials.keyFile", nil) })
+
+			got, err := readPassphrase("Passphrase: ", false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readPassphrase() = %q, %v, want an error about %s", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("readPassphrase() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// helperProtocol identifies hexa secrets among the credentials of a shared helper
+const helperProtocol = "hexa"
+
+// HelperStore delegates to an external command speaking the git credential helper
+// protocol (https://git-scm.com/docs/gitcredentials#_custom_helpers): the command is
+// called with get, store or erase and reads protocol=hexa, host=<service> and
+// username=<account> on stdin, so existing helpers (osxkeychain, libsecret, manager...) work.
+type HelperStore struct {
+	command []string
+}
+
+// NewHelperStore returns a store running helper, e.g. "git credential-osxkeychain"
+func NewHelperStore(helper string) *HelperStore {
+	return &HelperStore{command: strings.Fields(helper)}
+}
+
+// Get asks the helper for the secret
+func (s *HelperStore) Get(name string) (string, error) {
+	out, err := s.run("get", name, "")
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if secret, ok := strings.CutPrefix(scanner.Text(), "password="); ok && secret != "" {
+			return secret, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// Set asks the helper to store the secret
+func (s *HelperStore) Set(name, secret string) error {
+	if strings.ContainsAny(secret, "\n\r") {
+		return fmt.Errorf("secrets stored by a credential helper cannot contain newlines")
+	}
+	_, err := s.run("store", name, secret)
+	return err
+}
+
+// Delete asks the helper to erase the secret
+func (s *HelperStore) Delete(name string) error {
+	_, err := s.run("erase", name, "")
+	return err
+}
+
+// run calls the helper with an action and the credential description on stdin
+func (s *HelperStore) run(action, name, secret string) ([]byte, error) {
+	if len(s.command) == 0 {
+		return nil, fmt.Errorf("credentials.helper is empty")
+	}
+
+	service, account, _ := strings.Cut(name, "/")
+	input := fmt.Sprintf("protocol=%s\nhost=%s\nusername=%s\n", helperProtocol, service, account)
+	if secret != "" {
+		input += "password=" + secret + "\n"
+	}
+	input += "\n"
+
+	args := append(append([]string{}, s.command[1:]...), action)
+	command := exec.Command(s.command[0], args...)
+	command.Stdin = strings.NewReader(input)
+	command.Stderr = os.Stderr // Helpers may prompt or explain failures
+	out, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper '%s %s': %w", strings.Join(s.command, " "), action, err)
+	}
+	return out, nil
+}
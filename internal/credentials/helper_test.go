@@ -0,0 +1,72 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeHelper writes a credential helper script logging its action and stdin to a file,
+// and answering get with output. It returns the helper command and the log path.
+func fakeHelper(t *testing.T, output string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	script := filepath.Join(dir, "git-credential-fake")
+	content := "#!/bin/sh\n" +
+		"echo \"action=$2 option=$1\" >> " + log + "\n" +
+		"cat >> " + log + "\n" +
+		"if [ \"$2\" = get ]; then printf '%s' '" + output + "'; fi\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return script + " --fake-option", log
+}
+
+func TestHelperStoreProtocol(t *testing.T) {
+	helper, log := fakeHelper(t, "protocol=hexa\nhost=jira\nusername=default\npassword=s3cret\n")
+	store := NewHelperStore(helper)
+
+	if err := store.Set("jira/default", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := store.Get("jira/default")
+	if err != nil || secret != "s3cret" {
+		t.Errorf("Get() = %q, %v, want s3cret", secret, err)
+	}
+	if err := store.Delete("jira/default"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "action=store option=--fake-option\nprotocol=hexa\nhost=jira\nusername=default\npassword=s3cret\n\n" +
+		"action=get option=--fake-option\nprotocol=hexa\nhost=jira\nusername=default\n\n" +
+		"action=erase option=--fake-option\nprotocol=hexa\nhost=jira\nusername=default\n\n"
+	if string(data) != want {
+		t.Errorf("helper calls =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestHelperStoreErrors(t *testing.T) {
+	helper, _ := fakeHelper(t, "protocol=hexa\nhost=jira\n")
+	store := NewHelperStore(helper)
+
+	if _, err := store.Get("jira/default"); err != ErrNotFound {
+		t.Errorf("Get() without password error = %v, want ErrNotFound", err)
+	}
+	if err := store.Set("jira/default", "two\nlines"); err == nil {
+		t.Error("Set() accepted a secret with a newline")
+	}
+
+	failing := NewHelperStore("false")
+	if _, err := failing.Get("jira/default"); err == nil || !strings.Contains(err.Error(), "credential helper 'false get'") {
+		t.Errorf("Get() error = %v, want the helper failure", err)
+	}
+	if _, err := NewHelperStore("").Get("jira/default"); err == nil {
+		t.Error("Get() succeeded without helper command")
+	}
+}
@@ -0,0 +1,73 @@
+package credentials
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// readPassphrase returns the passphrase of the encrypted store: HEXA_CREDENTIALS_PASSPHRASE,
+// then the content of credentials.keyFile, then a prompt (typed twice when confirm is set)
+func readPassphrase(prompt string, confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	if keyFile := viper.GetString("credentials.keyFile"); keyFile != "" {
+		if strings.HasPrefix(keyFile, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				keyFile = home + keyFile[1:]
+			}
+		}
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return "", fmt.Errorf("reading credentials.keyFile: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no passphrase for the credentials store: set %s or credentials.keyFile", PassphraseEnv)
+	}
+
+	passphrase, err := ReadSecret(prompt)
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := ReadSecret("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// ReadSecret reads a secret from the terminal without echo, or a line from stdin
+// when it is not a terminal (e.g. `echo $TOKEN | hexa auth set jira/default`)
+func ReadSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("reading secret: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading secret: %w", err)
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,129 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Version is the HAR specification version written by hexa
+const Version = "1.2"
+
+// File is the root object of a HAR file
+type File struct {
+	Log Log `json:"log"`
+}
+
+// Log holds the recorded entries
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator identifies the application that recorded the log
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is one request/response exchange
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"` // RFC 3339 with milliseconds
+	Time            float64  `json:"time"`            // Total duration in milliseconds
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+	Comment         string   `json:"comment,omitempty"` // Transport error, if any
+}
+
+// Request describes the request sent
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	Cookies     []NameValue `json:"cookies"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+	PostData    *PostData   `json:"postData,omitempty"`
+}
+
+// Response describes the response received
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []NameValue `json:"headers"`
+	Cookies     []NameValue `json:"cookies"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// NameValue is a header, query parameter or cookie
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a request
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is the body of a response
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Timings breaks down the entry duration in milliseconds (-1 when unknown)
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// New returns an empty HAR file created by hexa
+func New(creatorVersion string) *File {
+	return &File{Log: Log{
+		Version: Version,
+		Creator: Creator{Name: "hexa", Version: creatorVersion},
+		Entries: []Entry{},
+	}}
+}
+
+// Write saves a HAR file (0600: responses may contain private Jira data)
+func Write(path string, file *File) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding HAR: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing HAR file: %w", err)
+	}
+
+	return nil
+}
+
+// Read loads a HAR file
+func Read(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading HAR file: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decoding HAR file %s: %w", path, err)
+	}
+
+	return &file, nil
+}
@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/spf13/viper"
+)
+
+// Authentication types accepted by jira.auth.type
+const (
+	AuthPAT   = "pat"   // Data Center personal access token: Bearer jira.token (default)
+	AuthBasic = "basic" // Data Center basic auth: jira.auth.username + jira.token (password or token)
+	AuthCloud = "cloud" // Jira Cloud: jira.auth.email + jira.token (API token)
+	AuthOAuth = "oauth" // OAuth 2.0 session opened by 'hexa jira login' (Data Center or Cloud)
+)
+
+// AuthTypes lists the valid jira.auth.type values
+var AuthTypes = []string{AuthPAT, AuthBasic, AuthCloud, AuthOAuth}
+
+// AuthType returns the configured authentication type (pat by default)
+func AuthType() string {
+	authType := strings.ToLower(strings.TrimSpace(viper.GetString("jira.auth.type")))
+	if authType == "" {
+		return AuthPAT
+	}
+	return authType
+}
+
+// IsCloud reports whether the configured Jira is a Jira Cloud instance
+func IsCloud() bool {
+	switch AuthType() {
+	case AuthCloud:
+		return true
+	case AuthOAuth:
+		return OAuthProvider() == ProviderCloud
+	default:
+		return false
+	}
+}
+
+// authorize sets the Authorization header according to jira.auth.type
+func authorize(req *http.Request) error {
+	if AuthType() == AuthOAuth {
+		accessToken, err := accessToken(req.Context())
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		return nil
+	}
+
+	// jira.token may reference the credentials store (secret://jira/default)
+	token, err := credentials.GetString("jira.token")
+	if err != nil {
+		return err
+	}
+
+	switch AuthType() {
+	case AuthPAT:
+		req.Header.Set("Authorization", "Bearer "+token)
+	case AuthBasic:
+		username := viper.GetString("jira.auth.username")
+		if username == "" {
+			return fmt.Errorf("jira.auth.username must be configured when jira.auth.type is %s", AuthBasic)
+		}
+		req.SetBasicAuth(username, token)
+	case AuthCloud:
+		// The account email may already be configured for the "me" filter
+		email := viper.GetString("jira.auth.email")
+		if email == "" {
+			email = viper.GetString("jira.userEmail")
+		}
+		if email == "" {
+			return fmt.Errorf("jira.auth.email must be configured when jira.auth.type is %s", AuthCloud)
+		}
+		req.SetBasicAuth(email, token)
+	default:
+		return fmt.Errorf("invalid jira.auth.type '%s' (valid: %s)", AuthType(), strings.Join(AuthTypes, ", "))
+	}
+
+	return nil
+}
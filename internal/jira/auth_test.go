@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		want    string // Authorization header
+		wantErr bool
+	}{
+		{
+			name:   "personal access token by default",
+			config: map[string]string{"jira.token": "tok"},
+			want:   "Bearer tok",
+		},
+		{
+			name:   "basic",
+			config: map[string]string{"jira.auth.type": "Basic", "jira.auth.username": "jdoe", "jira.token": "secret"},
+			want:   basicAuth("jdoe", "secret"),
+		},
+		{
+			name:    "basic without username",
+			config:  map[string]string{"jira.auth.type": AuthBasic, "jira.token": "secret"},
+			wantErr: true,
+		},
+		{
+			name:   "cloud",
+			config: map[string]string{"jira.auth.type": AuthCloud, "jira.auth.email": "me@example.com", "jira.token": "api"},
+			want:   basicAuth("me@example.com", "api"),
+		},
+		{
+			name:   "cloud with the filter email",
+			config: map[string]string{"jira.auth.type": AuthCloud, "jira.userEmail": "me@example.com", "jira.token": "api"},
+			want:   basicAuth("me@example.com", "api"),
+		},
+		{
+			name:    "cloud without email",
+			config:  map[string]string{"jira.auth.type": AuthCloud, "jira.token": "api"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			config:  map[string]string{"jira.auth.type": "kerberos", "jira.token": "tok"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setAuthConfig(t, tt.config)
+			req, err := http.NewRequest("GET", "https://jira.example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = authorize(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authorize() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCloud(t *testing.T) {
+	for authType, want := range map[string]bool{"": false, AuthPAT: false, AuthBasic: false, AuthCloud: true, " Cloud ": true} {
+		setAuthConfig(t, map[string]string{"jira.auth.type": authType})
+		if got := IsCloud(); got != want {
+			t.Errorf("IsCloud() with jira.auth.type %q = %v, want %v", authType, got, want)
+		}
+	}
+}
+
+// basicAuth returns the Authorization header of basic credentials
+func basicAuth(username, password string) string {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.SetBasicAuth(username, password)
+	return req.Header.Get("Authorization")
+}
@@ -0,0 +1,180 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/logger"
+)
+
+// ErrOffline is returned instead of calling Jira API when offline mode is enabled
+var ErrOffline = errors.New("offline mode: Jira API is not reachable")
+
+// offline is set by --offline, or automatically after the first connection error.
+// It is atomic because sprint pages are fetched concurrently.
+var offline atomic.Bool
+
+// SetOffline enables or disables offline mode for every Jira API call
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// transport sends every Jira API request; replaced to trace or replay exchanges
+var transport http.RoundTripper = http.DefaultTransport
+
+// SetTransport replaces the transport used for Jira API calls (nil restores the default)
+func SetTransport(rt http.RoundTripper) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport = rt
+}
+
+// Transport returns the transport used for Jira API calls
+func Transport() http.RoundTripper {
+	return transport
+}
+
+// DefaultRequestTimeout bounds each Jira API call when --timeout is not given, so that an
+// unresponsive Jira never hangs a command
+const DefaultRequestTimeout = 30 * time.Second
+
+// requestTimeout bounds each Jira API call, see SetRequestTimeout
+var requestTimeout = DefaultRequestTimeout
+
+// SetRequestTimeout replaces the timeout of each Jira API call, e.g. with --timeout
+// (0 restores DefaultRequestTimeout)
+func SetRequestTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	requestTimeout = timeout
+}
+
+// newClient returns an HTTP client using the Jira transport, bounded by the request timeout
+func newClient() *http.Client {
+	return &http.Client{Transport: transport, Timeout: requestTimeout}
+}
+
+// IsOffline reports whether Jira API calls are currently refused
+func IsOffline() bool {
+	return offline.Load()
+}
+
+// IsConnectionError reports whether err means Jira could not be reached (DNS, refused, timeout, offline mode).
+// A request canceled by Ctrl-C or --timeout is not a connection error.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrOffline) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	return isNetworkError(err)
+}
+
+// IsUnsentError reports whether err guarantees that the request never reached Jira: offline
+// mode, DNS or dial failure. After a timeout or a reset connection, a write may have been applied.
+func IsUnsentError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if !isNetworkError(err) {
+		return errors.Is(err, ErrOffline) // Refused before sending in offline mode
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isNetworkError reports whether err is a DNS, dial or timeout error
+func isNetworkError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// newRequest builds an authenticated Jira API request (see jira.auth.type), canceled with ctx
+func newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Add("Accept", "application/json")
+	if err := authorize(req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// do executes a Jira API request. Every call (read or write) is refused in offline mode,
+// and a connection error switches the rest of the command to offline mode.
+func do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if offline.Load() {
+		return nil, ErrOffline
+	}
+
+	logger.Debug("Jira API call", "method", req.Method, "url", req.URL.String())
+	resp, err := client.Do(req)
+	if err != nil {
+		// A canceled request says nothing about Jira being reachable
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if isNetworkError(err) {
+			offline.Store(true)
+			return nil, fmt.Errorf("%w (%w)", ErrOffline, err)
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// getJSON executes a GET request and decodes the JSON response into out
+func getJSON(ctx context.Context, apiURL string, out any) error {
+	req, err := newRequest(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := do(newClient(), req)
+	if err != nil {
+		return fmt.Errorf("calling Jira API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jira API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
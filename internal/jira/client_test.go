@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestIsUnsentError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"offline mode", ErrOffline, true},
+		{"dns failure", fmt.Errorf("%w (%w)", ErrOffline, &net.DNSError{Err: "no such host", Name: "jira"}), true},
+		{"connection refused", fmt.Errorf("%w (%w)", ErrOffline, &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{"connection reset", fmt.Errorf("%w (%w)", ErrOffline, &net.OpError{Op: "read", Err: errors.New("connection reset")}), false},
+		{"response timeout", fmt.Errorf("%w (%w)", ErrOffline, &url.Error{Op: "Post", URL: "http://jira", Err: timeoutError{}}), false},
+		{"canceled", context.Canceled, false},
+		{"http error", errors.New("jira API returned status 500"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnsentError(tt.err); got != tt.want {
+				t.Errorf("IsUnsentError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// timeoutError is a net.Error timing out, like http.Client.Timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "Client.Timeout exceeded while awaiting headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
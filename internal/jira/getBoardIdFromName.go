@@ -1,13 +1,13 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 
-	"github.com/spf13/viper"
+	"github.com/hyphaene/hexa/internal/logger"
 )
 
 // BoardListResponse représente la réponse de l'API /rest/agile/1.0/board
@@ -34,29 +34,24 @@ type Board struct {
 }
 
 // GetBoardIdFromName récupère l'ID d'un board depuis son nom
-func GetBoardIdFromName(boardName string) (int, error) {
-	jiraToken := viper.GetString("jira.token")
-	baseURL := viper.GetString("jira.url")
+func GetBoardIdFromName(ctx context.Context, boardName string) (int, error) {
 
 	// URL encode le nom du board
 	encodedName := url.QueryEscape(boardName)
-	apiURL := fmt.Sprintf("%s/rest/agile/1.0/board?name=%s", baseURL, encodedName)
+	apiURL := fmt.Sprintf("%s/rest/agile/1.0/board?name=%s", baseURL(), encodedName)
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+	req, err := newRequest(ctx, "GET", apiURL, nil)
 	if err != nil {
-		return 0, fmt.Errorf("creating request: %w", err)
+		return 0, err
 	}
 
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", "Bearer "+jiraToken)
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := do(newClient(), req)
 	if err != nil {
 		return 0, fmt.Errorf("executing request: %w", err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
-			log.Printf("closing response body: %v", cerr)
+			logger.Debug("closing response body", "error", cerr)
 		}
 	}()
 
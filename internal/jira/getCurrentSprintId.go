@@ -1,12 +1,13 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
+	"github.com/hyphaene/hexa/internal/logger"
 	"github.com/spf13/viper"
 )
 
@@ -34,37 +35,27 @@ type Sprint struct {
 }
 
 // GetSprintIdFromNumber resolves sprint ID from a sprint number (eg. 35 -> "Sprint SEE x SOP 35")
-func GetSprintIdFromNumber(sprintNumber int) (int, error) {
+func GetSprintIdFromNumber(ctx context.Context, sprintNumber int) (int, error) {
 	boardName := viper.GetString("jira.boardName")
 	if boardName == "" {
 		return 0, fmt.Errorf("jira.boardName not configured")
 	}
 
-	// Get board ID
-	var boardID int
-	if viper.IsSet("jira.boardId") {
-		boardID = viper.GetInt("jira.boardId")
-	} else {
-		var err error
-		boardID, err = GetBoardIdFromName(boardName)
-		if err != nil {
-			return 0, fmt.Errorf("resolving board ID: %w", err)
-		}
+	boardID, err := ResolveBoardID(ctx)
+	if err != nil {
+		return 0, err
 	}
 
 	// Search for sprint matching "Sprint {boardName} {number}"
 	sprintName := fmt.Sprintf("Sprint %s %d", boardName, sprintNumber)
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?maxResults=500", viper.GetString("jira.url"), boardID)
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?maxResults=500", baseURL(), boardID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := newRequest(ctx, "GET", url, nil)
 	if err != nil {
-		return 0, fmt.Errorf("creating request: %w", err)
+		return 0, err
 	}
 
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", "Bearer "+viper.GetString("jira.token"))
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := do(newClient(), req)
 	if err != nil {
 		return 0, fmt.Errorf("calling API: %w", err)
 	}
@@ -89,44 +80,52 @@ func GetSprintIdFromNumber(sprintNumber int) (int, error) {
 	return 0, fmt.Errorf("sprint '%s' not found", sprintName)
 }
 
-func GetCurrentSprintId() (int, error) {
-	jiraToken := viper.GetString("jira.token")
-
+// ResolveBoardID returns jira.boardId, or resolves it from jira.boardName via API
+func ResolveBoardID(ctx context.Context) (int, error) {
 	// Priorité 1: utiliser jira.boardId si présent (évite appel API)
-	var boardID int
 	if viper.IsSet("jira.boardId") {
-		boardID = viper.GetInt("jira.boardId")
-	} else {
-		// Priorité 2: résoudre via jira.boardName (fallback)
-		boardName := viper.GetString("jira.boardName")
-		if boardName == "" {
-			return 0, fmt.Errorf("neither jira.boardId nor jira.boardName is configured. Run 'hexa jira init --board-name \"YOUR_BOARD\" --config-path .hexa.local.yml' to initialize")
-		}
-		var err error
-		boardID, err = GetBoardIdFromName(boardName)
-		if err != nil {
-			return 0, fmt.Errorf("resolving board ID from name '%s': %w. Consider running 'hexa jira init' to cache the board ID", boardName, err)
-		}
+		return viper.GetInt("jira.boardId"), nil
 	}
 
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?state=active", viper.GetString("jira.url"), boardID)
+	// Priorité 2: résoudre via jira.boardName (fallback)
+	boardName := viper.GetString("jira.boardName")
+	if boardName == "" {
+		return 0, fmt.Errorf("neither jira.boardId nor jira.boardName is configured. Run 'hexa jira init --board-name \"YOUR_BOARD\" --config-path .hexa.local.yml' to initialize")
+	}
+	boardID, err := GetBoardIdFromName(ctx, boardName)
+	if err != nil {
+		return 0, fmt.Errorf("resolving board ID from name '%s': %w. Consider running 'hexa jira init' to cache the board ID", boardName, err)
+	}
+
+	return boardID, nil
+}
 
-	resp, err := http.NewRequest("GET", url, nil)
+// GetCurrentSprintId returns the ID of the active sprint of the configured board
+func GetCurrentSprintId(ctx context.Context) (int, error) {
+	boardID, err := ResolveBoardID(ctx)
 	if err != nil {
-		log.Fatalln(err)
+		return 0, err
 	}
 
-	resp.Header.Add("Accept", "application/json")
-	resp.Header.Add("Authorization", "Bearer "+jiraToken)
+	return GetActiveSprintId(ctx, boardID)
+}
+
+// GetActiveSprintId returns the ID of the active sprint of a board
+func GetActiveSprintId(ctx context.Context, boardID int) (int, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?state=active", baseURL(), boardID)
 
-	response, err := http.DefaultClient.Do(resp)
+	req, err := newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
 
+	response, err := do(newClient(), req)
 	if err != nil {
-		log.Fatalln(err)
+		return 0, fmt.Errorf("calling API: %w", err)
 	}
 	defer func() {
 		if cerr := response.Body.Close(); cerr != nil {
-			log.Printf("closing response body: %v", cerr)
+			logger.Debug("closing response body", "error", cerr)
 		}
 	}()
 
@@ -140,5 +139,9 @@ func GetCurrentSprintId() (int, error) {
 		return 0, fmt.Errorf("decoding response: %w", err)
 	}
 
+	if len(sprintResp.Values) == 0 {
+		return 0, fmt.Errorf("no active sprint found on board %d", boardID)
+	}
+
 	return sprintResp.Values[0].ID, nil
 }
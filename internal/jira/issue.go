@@ -0,0 +1,56 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Transition represents a workflow transition available on a ticket
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"` // e.g., "Start Progress"
+	To   Status `json:"to"`   // Target status
+}
+
+// transitionsResponse represents the API response for /issue/{key}/transitions
+type transitionsResponse struct {
+	Transitions []Transition `json:"transitions"`
+}
+
+// FetchIssue fetches a single ticket with the fields used for display and conflict detection
+func FetchIssue(ctx context.Context, issueKey string) (*Ticket, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status,assignee,priority,updated",
+		baseURL(), url.PathEscape(issueKey))
+
+	var ticket Ticket
+	if err := getJSON(ctx, apiURL, &ticket); err != nil {
+		return nil, err
+	}
+
+	return &ticket, nil
+}
+
+// FetchTransitions lists the transitions currently available on a ticket
+func FetchTransitions(ctx context.Context, issueKey string) ([]Transition, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", baseURL(), url.PathEscape(issueKey))
+
+	var resp transitionsResponse
+	if err := getJSON(ctx, apiURL, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Transitions, nil
+}
+
+// FetchStatuses lists the statuses defined in Jira
+func FetchStatuses(ctx context.Context) ([]Status, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/status", baseURL())
+
+	var statuses []Status
+	if err := getJSON(ctx, apiURL, &statuses); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
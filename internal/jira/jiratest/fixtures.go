@@ -0,0 +1,207 @@
+// Package jiratest provides an in-memory fake Jira server and record/replay transports,
+// so that Jira calls and commands can be exercised without a live instance.
+package jiratest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hyphaene/hexa/internal/har"
+	"github.com/hyphaene/hexa/internal/jira"
+)
+
+// RecordEnv enables recording in FixtureTransport when set to "true"
+const RecordEnv = "HEXA_RECORD_FIXTURES"
+
+// Recorder is a transport recording real Jira exchanges into a HAR fixture file
+type Recorder struct {
+	tracer  *jira.Tracer
+	path    string
+	secrets []string
+}
+
+// NewRecorder records the exchanges sent through next (nil: http.DefaultTransport).
+// Secrets (token, password...) are replaced everywhere in the fixture when it is saved,
+// on top of the Authorization and cookie headers that are always redacted.
+func NewRecorder(path string, next http.RoundTripper, secrets ...string) *Recorder {
+	return &Recorder{tracer: jira.NewTracer(next), path: path, secrets: secrets}
+}
+
+// RoundTrip sends the request to Jira and records the exchange
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.tracer.RoundTrip(req)
+}
+
+// Save writes the recorded exchanges, scrubbed, to the fixture file
+func (r *Recorder) Save() error {
+	file := r.tracer.HAR("fixture")
+	Scrub(file, r.secrets...)
+	return har.Write(r.path, file)
+}
+
+// Scrub replaces every occurrence of the secrets in URLs, headers and bodies with jira.Redacted
+func Scrub(file *har.File, secrets ...string) {
+	replacements := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			replacements = append(replacements, secret, jira.Redacted)
+		}
+	}
+	if len(replacements) == 0 {
+		return
+	}
+	scrub := strings.NewReplacer(replacements...).Replace
+
+	for i := range file.Log.Entries {
+		entry := &file.Log.Entries[i]
+		entry.Request.URL = scrub(entry.Request.URL)
+		scrubValues(entry.Request.Headers, scrub)
+		scrubValues(entry.Request.QueryString, scrub)
+		if entry.Request.PostData != nil {
+			entry.Request.PostData.Text = scrub(entry.Request.PostData.Text)
+		}
+		scrubValues(entry.Response.Headers, scrub)
+		entry.Response.Content.Text = scrub(entry.Response.Content.Text)
+	}
+}
+
+func scrubValues(values []har.NameValue, scrub func(string) string) {
+	for i := range values {
+		values[i].Value = scrub(values[i].Value)
+	}
+}
+
+// Replayer answers requests with the responses of a HAR fixture, as a transport or as a server.
+// Requests are matched on method, path, query and body, regardless of the host they were recorded
+// against. Identical requests get the recorded responses in order, the last one being repeated.
+type Replayer struct {
+	mu      sync.Mutex
+	entries []har.Entry
+	used    []bool
+}
+
+// NewReplayer loads a HAR fixture (recorded by Recorder or saved with --trace-file)
+func NewReplayer(path string) (*Replayer, error) {
+	file, err := har.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{entries: file.Log.Entries, used: make([]bool, len(file.Log.Entries))}, nil
+}
+
+// RoundTrip returns the recorded response matching the request
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry, err := r.match(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body := entry.Response.Content.Text
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Response.Status, entry.Response.StatusText),
+		StatusCode:    entry.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        replayHeaders(entry.Response.Headers),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// ServeHTTP writes the recorded response matching the request, or 404 when there is none
+func (r *Replayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	entry, err := r.match(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	for name, values := range replayHeaders(entry.Response.Headers) {
+		if name == "Content-Length" {
+			continue // Recomputed from the body
+		}
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.WriteHeader(entry.Response.Status)
+	_, _ = io.WriteString(w, entry.Response.Content.Text)
+}
+
+// match returns the first unused entry matching req, or the last used one
+func (r *Replayer) match(req *http.Request) (*har.Entry, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	key := requestKey(req.Method, req.URL.String(), string(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, entry := range r.entries {
+		postData := ""
+		if entry.Request.PostData != nil {
+			postData = entry.Request.PostData.Text
+		}
+		if requestKey(entry.Request.Method, entry.Request.URL, postData) != key {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return &r.entries[i], nil
+		}
+		last = i
+	}
+	if last >= 0 {
+		return &r.entries[last], nil
+	}
+
+	return nil, fmt.Errorf("jiratest: no recorded response for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// requestKey identifies a request independently of the host and of the query parameters order
+func requestKey(method string, rawURL string, body string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL + " " + body
+	}
+	return method + " " + u.Path + "?" + u.Query().Encode() + " " + strings.TrimSpace(body)
+}
+
+func replayHeaders(values []har.NameValue) http.Header {
+	header := http.Header{}
+	for _, value := range values {
+		header.Add(value.Name, value.Value)
+	}
+	return header
+}
+
+// FixtureTransport returns a transport replaying the fixture at path, or recording it through
+// http.DefaultTransport when HEXA_RECORD_FIXTURES=true. The returned save function writes the
+// fixture after recording and does nothing when replaying.
+func FixtureTransport(path string, secrets ...string) (http.RoundTripper, func() error, error) {
+	if record, _ := strconv.ParseBool(os.Getenv(RecordEnv)); record {
+		recorder := NewRecorder(path, nil, secrets...)
+		return recorder, recorder.Save, nil
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return replayer, func() error { return nil }, nil
+}
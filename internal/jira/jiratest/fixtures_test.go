@@ -0,0 +1,117 @@
+package jiratest_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+	"github.com/hyphaene/hexa/internal/outbox"
+	"github.com/spf13/viper"
+)
+
+// record runs a sprint fetch and a comment against the fake server through FixtureTransport in
+// record mode, and returns the fixture path with the tickets fetched
+func record(t *testing.T) (string, []jira.Ticket) {
+	t.Helper()
+	server := newSprint(t, 60)
+	fixture := filepath.Join(t.TempDir(), "sprint.har")
+
+	t.Setenv(jiratest.RecordEnv, "true")
+	transport, save, err := jiratest.FixtureTransport(fixture, server.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jira.SetTransport(transport)
+	t.Cleanup(func() { jira.SetTransport(nil) })
+
+	tickets := exercise(t)
+	if err := save(); err != nil {
+		t.Fatal(err)
+	}
+	jira.SetTransport(nil)
+	return fixture, tickets
+}
+
+// exercise fetches the sprint and comments a ticket, the exchanges recorded then replayed
+func exercise(t *testing.T) []jira.Ticket {
+	t.Helper()
+	ctx := context.Background()
+	tickets, _, err := jira.FetchSprintTickets(ctx, sprintID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued, err := outbox.Submit(ctx, &outbox.Operation{Type: outbox.OpComment, IssueKey: "PROJ-1", Body: "recorded"}); err != nil || queued {
+		t.Fatalf("Submit() = %v, %v", queued, err)
+	}
+	return tickets
+}
+
+func TestRecordScrubsSecrets(t *testing.T) {
+	fixture, _ := record(t)
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "test-token") {
+		t.Error("the fixture contains the token")
+	}
+	if !strings.Contains(string(data), jira.Redacted) {
+		t.Error("the fixture has no redacted value")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, fixture string)
+	}{
+		{
+			name: "as a transport",
+			setup: func(t *testing.T, fixture string) {
+				transport, _, err := jiratest.FixtureTransport(fixture)
+				if err != nil {
+					t.Fatal(err)
+				}
+				jira.SetTransport(transport)
+				t.Cleanup(func() { jira.SetTransport(nil) })
+				viper.Set("jira.url", "http://jira.invalid") // Never dialed
+			},
+		},
+		{
+			name: "as a server",
+			setup: func(t *testing.T, fixture string) {
+				replayer, err := jiratest.NewReplayer(fixture)
+				if err != nil {
+					t.Fatal(err)
+				}
+				server := httptest.NewServer(replayer)
+				t.Cleanup(server.Close)
+				viper.Set("jira.url", server.URL)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, recorded := record(t)
+			t.Setenv(jiratest.RecordEnv, "")
+			tt.setup(t, fixture)
+
+			replayed := exercise(t)
+			if !slices.Equal(keysOf(replayed), keysOf(recorded)) {
+				t.Errorf("replayed %d tickets, recorded %d", len(replayed), len(recorded))
+			}
+
+			if _, _, err := jira.SearchIssues(context.Background(), "project = PROJ", jira.TicketFields, 0); err == nil {
+				t.Error("a request missing from the fixture succeeded")
+			}
+		})
+	}
+}
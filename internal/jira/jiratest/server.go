@@ -0,0 +1,673 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/spf13/viper"
+)
+
+// timeLayout is the format of Jira dates
+const timeLayout = "2006-01-02T15:04:05.000-0700"
+
+// DefaultPageSize is the maximum number of issues returned per page, like Jira's agile API
+const DefaultPageSize = 50
+
+// Server is an in-memory fake Jira implementing the endpoints used by hexa:
+// boards, sprints, sprint issues, issues, search, transitions, comments, assignee, labels,
+// statuses and myself. Writes update the in-memory state.
+type Server struct {
+	*httptest.Server
+
+	Token    string // Token required by every call, as Bearer or basic auth password (any if empty)
+	PageSize int    // Maximum issues per page (DefaultPageSize if 0)
+
+	mu       sync.Mutex
+	boards   []jira.Board
+	sprints  map[int][]jira.Sprint // By board ID
+	ranks    map[int][]string      // Issue keys of each sprint, in rank order
+	issues   map[string]*issue
+	order    []string // Issue keys in creation order
+	statuses []jira.Status
+	myself   jira.UserProfile
+	requests []string
+	failures map[string]int // Status answered to the requests starting with each prefix
+}
+
+// issue is the server-side state of a ticket
+type issue struct {
+	ticket      jira.Ticket
+	updated     time.Time
+	transitions []jira.Transition // nil: one transition per known status
+	comments    []string
+	labels      []string
+}
+
+// NewServer starts a fake Jira with a default user and the statuses of the status map
+func NewServer() *Server {
+	s := &Server{
+		Token:   "test-token",
+		sprints: map[int][]jira.Sprint{},
+		ranks:   map[int][]string{},
+		issues:  map[string]*issue{},
+		myself: jira.UserProfile{
+			AccountID:    "test-account",
+			Name:         "jdoe",
+			EmailAddress: "jdoe@example.com",
+			DisplayName:  "John Doe",
+		},
+	}
+	for _, key := range jira.ValidStatusKeys() {
+		if name, err := jira.MapStatusKey(key); err == nil {
+			s.statuses = append(s.statuses, jira.Status{Name: name})
+		}
+	}
+
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Configure points the global configuration (jira.url, jira.token) to the fake server
+func (s *Server) Configure() {
+	viper.Set("jira.url", s.URL)
+	viper.Set("jira.token", s.Token)
+}
+
+// SetMyself replaces the authenticated user
+func (s *Server) SetMyself(profile jira.UserProfile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.myself = profile
+}
+
+// AddBoard declares a board
+func (s *Server) AddBoard(id int, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boards = append(s.boards, jira.Board{ID: id, Name: name, Type: "scrum"})
+}
+
+// AddSprint declares a sprint on a board ("active", "future" or "closed" state)
+func (s *Server) AddSprint(boardID int, sprint jira.Sprint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sprint.OriginBoardID = boardID
+	s.sprints[boardID] = append(s.sprints[boardID], sprint)
+}
+
+// AddIssue adds a ticket at the end of a sprint
+func (s *Server) AddIssue(sprintID int, ticket jira.Ticket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.issues[ticket.Key]; !ok {
+		s.order = append(s.order, ticket.Key)
+	}
+	s.issues[ticket.Key] = &issue{ticket: ticket, updated: time.Now()}
+	s.ranks[sprintID] = append(s.ranks[sprintID], ticket.Key)
+}
+
+// UpdateIssue modifies a ticket as if edited in Jira (its updated date is bumped)
+func (s *Server) UpdateIssue(key string, update func(*jira.Ticket)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is, ok := s.issues[key]; ok {
+		update(&is.ticket)
+		is.updated = time.Now()
+	}
+}
+
+// Backdate moves the updated date of every ticket back by d, as if none had changed for d
+func (s *Server) Backdate(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, is := range s.issues {
+		is.updated = is.updated.Add(-d)
+	}
+}
+
+// RemoveIssue removes a ticket from a sprint
+func (s *Server) RemoveIssue(sprintID int, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.ranks[sprintID]
+	for i, k := range keys {
+		if k == key {
+			s.ranks[sprintID] = append(keys[:i:i], keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetTransitions restricts the transitions available on a ticket
+func (s *Server) SetTransitions(key string, transitions []jira.Transition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is, ok := s.issues[key]; ok {
+		is.transitions = transitions
+	}
+}
+
+// Issue returns the current state of a ticket
+func (s *Server) Issue(key string) (jira.Ticket, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	is, ok := s.issues[key]
+	if !ok {
+		return jira.Ticket{}, false
+	}
+	return is.ticket, true
+}
+
+// Comments returns the comments added to a ticket
+func (s *Server) Comments(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is, ok := s.issues[key]; ok {
+		return append([]string(nil), is.comments...)
+	}
+	return nil
+}
+
+// Labels returns the labels of a ticket
+func (s *Server) Labels(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is, ok := s.issues[key]; ok {
+		return append([]string(nil), is.labels...)
+	}
+	return nil
+}
+
+// Fail answers status to every request starting with prefix, as "METHOD /path?query"
+// (e.g. "GET /rest/agile/1.0/sprint/42/issue?startAt=20&" for one page of a sprint)
+func (s *Server) Fail(prefix string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == nil {
+		s.failures = map[string]int{}
+	}
+	s.failures[prefix] = status
+}
+
+// Requests returns the requests received so far, as "METHOD /path?query"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/agile/1.0/board", s.handleBoards)
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}/sprint", s.handleSprints)
+	mux.HandleFunc("GET /rest/agile/1.0/sprint/{id}/issue", s.handleSprintIssues)
+	mux.HandleFunc("GET /rest/api/2/search", s.handleSearch)
+	mux.HandleFunc("GET /rest/api/3/search/jql", s.handleSearchJQL)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}", s.handleIssue)
+	mux.HandleFunc("PUT /rest/api/2/issue/{key}", s.handleEditIssue)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/transitions", s.handleTransitions)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/transitions", s.handleDoTransition)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/comment", s.handleComment)
+	mux.HandleFunc("PUT /rest/api/2/issue/{key}/assignee", s.handleAssign)
+	mux.HandleFunc("GET /rest/api/2/status", s.handleStatuses)
+	mux.HandleFunc("GET /rest/api/{version}/myself", s.handleMyself)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.RequestURI()
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		failure := 0
+		for prefix, status := range s.failures {
+			if strings.HasPrefix(request, prefix) {
+				failure = status
+			}
+		}
+		s.mu.Unlock()
+
+		if failure != 0 {
+			writeError(w, failure, "Injected failure")
+			return
+		}
+
+		if s.Token != "" && !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorized accepts the token as Bearer (pat) or as basic auth password (basic, cloud)
+func (s *Server) authorized(r *http.Request) bool {
+	if _, password, ok := r.BasicAuth(); ok {
+		return password == s.Token
+	}
+	return r.Header.Get("Authorization") == "Bearer "+s.Token
+}
+
+func (s *Server) handleBoards(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.URL.Query().Get("name")
+	boards := []jira.Board{}
+	for _, board := range s.boards {
+		if name == "" || strings.Contains(strings.ToLower(board.Name), strings.ToLower(name)) {
+			boards = append(boards, board)
+		}
+	}
+
+	writeJSON(w, jira.BoardListResponse{MaxResults: 50, Total: len(boards), IsLast: true, Values: boards})
+}
+
+func (s *Server) handleSprints(w http.ResponseWriter, r *http.Request) {
+	boardID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid board ID")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := r.URL.Query().Get("state")
+	sprints := []jira.Sprint{}
+	for _, sprint := range s.sprints[boardID] {
+		if state == "" || strings.Contains(state, sprint.State) {
+			sprints = append(sprints, sprint)
+		}
+	}
+
+	writeJSON(w, jira.SprintListResponse{MaxResults: 50, IsLast: true, Values: sprints})
+}
+
+func (s *Server) handleSprintIssues(w http.ResponseWriter, r *http.Request) {
+	sprintID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid sprint ID")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tickets := make([]jira.Ticket, 0, len(s.ranks[sprintID]))
+	for _, key := range s.ranks[sprintID] {
+		tickets = append(tickets, s.issues[key].view())
+	}
+
+	startAt, maxResults := s.page(r)
+	page, total := paginate(tickets, startAt, maxResults)
+	writeJSON(w, jira.SprintIssuesResponse{
+		MaxResults: maxResults,
+		StartAt:    startAt,
+		Total:      total,
+		IsLast:     startAt+len(page) >= total,
+		Issues:     page,
+	})
+}
+
+// JQL understood by the search endpoint: clauses joined with AND, an ORDER BY suffix being ignored
+// (results keep the rank order)
+var (
+	andSeparator   = regexp.MustCompile(`(?i)\s+AND\s+`)
+	orderBySuffix  = regexp.MustCompile(`(?i)\s+ORDER\s+BY\s+.*$`)
+	sprintClause   = regexp.MustCompile(`(?i)^sprint\s*=\s*(\d+)$`)
+	updatedClause  = regexp.MustCompile(`(?i)^updated\s*>=\s*"?-(\d+)([mhdw])"?$`)
+	keyClause      = regexp.MustCompile(`(?i)^(?:key|issuekey)\s*(?:=\s*"?([^"\s]+)"?|in\s*\(([^)]*)\))$`)
+	projectClause  = regexp.MustCompile(`(?i)^project\s*=\s*"?([^"\s]+)"?$`)
+	statusClause   = regexp.MustCompile(`(?i)^status\s*(=|!=)\s*"?([^"]+?)"?$`)
+	assigneeClause = regexp.MustCompile(`(?i)^assignee\s*(?:=\s*(currentUser\(\)|"?[^"]+?"?)|is\s+(empty))$`)
+)
+
+// units of relative JQL dates
+var jqlUnits = map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+// issueFilter is a parsed JQL clause, called with the server locked
+type issueFilter func(s *Server, is *issue) bool
+
+// parseJQL returns the sprint of a "sprint = N" clause (-1 without) and the other clauses as filters
+func parseJQL(jql string) (int, []issueFilter, error) {
+	sprintID := -1
+	var filters []issueFilter
+
+	jql = orderBySuffix.ReplaceAllString(strings.TrimSpace(jql), "")
+	if jql == "" {
+		return sprintID, nil, nil
+	}
+	for _, clause := range andSeparator.Split(jql, -1) {
+		clause = strings.TrimSpace(clause)
+		if m := sprintClause.FindStringSubmatch(clause); m != nil {
+			sprintID, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if m := updatedClause.FindStringSubmatch(clause); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			since := time.Now().Add(-time.Duration(n) * jqlUnits[strings.ToLower(m[2])])
+			filters = append(filters, func(_ *Server, is *issue) bool { return !is.updated.Before(since) })
+			continue
+		}
+		if m := keyClause.FindStringSubmatch(clause); m != nil {
+			keys := map[string]bool{}
+			for _, key := range strings.Split(m[1]+","+m[2], ",") {
+				if key = strings.Trim(strings.TrimSpace(key), `"`); key != "" {
+					keys[strings.ToUpper(key)] = true
+				}
+			}
+			filters = append(filters, func(_ *Server, is *issue) bool { return keys[strings.ToUpper(is.ticket.Key)] })
+			continue
+		}
+		if m := projectClause.FindStringSubmatch(clause); m != nil {
+			prefix := strings.ToUpper(m[1]) + "-"
+			filters = append(filters, func(_ *Server, is *issue) bool { return strings.HasPrefix(strings.ToUpper(is.ticket.Key), prefix) })
+			continue
+		}
+		if m := statusClause.FindStringSubmatch(clause); m != nil {
+			negate, status := m[1] == "!=", m[2]
+			filters = append(filters, func(_ *Server, is *issue) bool {
+				return strings.EqualFold(is.ticket.Fields.Status.Name, status) != negate
+			})
+			continue
+		}
+		if m := assigneeClause.FindStringSubmatch(clause); m != nil {
+			user := strings.Trim(m[1], `"`)
+			filters = append(filters, func(s *Server, is *issue) bool {
+				assignee := is.ticket.Fields.Assignee
+				switch {
+				case m[2] != "":
+					return assignee == nil
+				case assignee == nil:
+					return false
+				case strings.EqualFold(user, "currentUser()"):
+					return sameUser(assignee, s.myself.AccountID, s.myself.Name)
+				default:
+					return sameUser(assignee, user, user)
+				}
+			})
+			continue
+		}
+		return 0, nil, fmt.Errorf("unsupported JQL clause: %s", clause)
+	}
+	return sprintID, filters, nil
+}
+
+// sameUser reports whether the assignee has the account ID or the username (empty values never match)
+func sameUser(assignee *jira.Assignee, accountID string, name string) bool {
+	return (accountID != "" && assignee.AccountID == accountID) || (name != "" && strings.EqualFold(assignee.Name, name))
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	tickets, ok := s.search(w, r)
+	if !ok {
+		return
+	}
+
+	startAt, maxResults := s.page(r)
+	page, total := paginate(tickets, startAt, maxResults)
+	writeJSON(w, jira.SprintIssuesResponse{MaxResults: maxResults, StartAt: startAt, Total: total, Issues: page})
+}
+
+// handleSearchJQL serves the Cloud search: no total, pages chained by an opaque nextPageToken
+func (s *Server) handleSearchJQL(w http.ResponseWriter, r *http.Request) {
+	tickets, ok := s.search(w, r)
+	if !ok {
+		return
+	}
+
+	startAt := 0
+	if token := r.URL.Query().Get("nextPageToken"); token != "" {
+		var err error
+		if startAt, err = strconv.Atoi(strings.TrimPrefix(token, "page-")); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid nextPageToken")
+			return
+		}
+	}
+	_, maxResults := s.page(r)
+	page, total := paginate(tickets, startAt, maxResults)
+
+	resp := map[string]any{"issues": page, "isLast": startAt+len(page) >= total}
+	if startAt+len(page) < total {
+		resp["nextPageToken"] = fmt.Sprintf("page-%d", startAt+len(page))
+	}
+	writeJSON(w, resp)
+}
+
+// search returns the issues matching the jql parameter, with only their key if fields=key
+func (s *Server) search(w http.ResponseWriter, r *http.Request) ([]jira.Ticket, bool) {
+	sprintID, filters, err := parseJQL(r.URL.Query().Get("jql"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := s.order
+	if sprintID >= 0 {
+		candidates = s.ranks[sprintID]
+	}
+
+	keysOnly := r.URL.Query().Get("fields") == "key"
+	tickets := []jira.Ticket{}
+	for _, key := range candidates {
+		is := s.issues[key]
+		if !matches(s, is, filters) {
+			continue
+		}
+		if keysOnly {
+			tickets = append(tickets, jira.Ticket{Key: key})
+		} else {
+			tickets = append(tickets, is.view())
+		}
+	}
+	return tickets, true
+}
+
+// matches reports whether the issue passes every filter
+func matches(s *Server, is *issue, filters []issueFilter) bool {
+	for _, filter := range filters {
+		if !filter(s, is) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) handleIssue(w http.ResponseWriter, r *http.Request) {
+	s.withIssue(w, r, func(is *issue) {
+		writeJSON(w, is.view())
+	})
+}
+
+func (s *Server) handleEditIssue(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Update struct {
+			Labels []map[string]string `json:"labels"`
+		} `json:"update"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	s.withIssue(w, r, func(is *issue) {
+		for _, op := range payload.Update.Labels {
+			if label, ok := op["add"]; ok {
+				is.labels = append(is.labels, label)
+			}
+		}
+		is.updated = time.Now()
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) handleTransitions(w http.ResponseWriter, r *http.Request) {
+	s.withIssue(w, r, func(is *issue) {
+		writeJSON(w, map[string][]jira.Transition{"transitions": s.transitionsOf(is)})
+	})
+}
+
+func (s *Server) handleDoTransition(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	s.withIssue(w, r, func(is *issue) {
+		for _, transition := range s.transitionsOf(is) {
+			if transition.ID == payload.Transition.ID {
+				is.ticket.Fields.Status = transition.To
+				is.updated = time.Now()
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("transition %s is not valid for %s", payload.Transition.ID, is.ticket.Key))
+	})
+}
+
+func (s *Server) handleComment(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	s.withIssue(w, r, func(is *issue) {
+		is.comments = append(is.comments, payload.Body)
+		is.updated = time.Now()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, map[string]string{"id": strconv.Itoa(len(is.comments)), "body": payload.Body})
+	})
+}
+
+func (s *Server) handleAssign(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Name      string `json:"name"`
+		AccountID string `json:"accountId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	s.withIssue(w, r, func(is *issue) {
+		switch {
+		case payload.Name == "" && payload.AccountID == "":
+			is.ticket.Fields.Assignee = nil
+		case (payload.Name != "" && payload.Name == s.myself.Name) || (payload.AccountID != "" && payload.AccountID == s.myself.AccountID):
+			is.ticket.Fields.Assignee = &jira.Assignee{
+				AccountID:    s.myself.AccountID,
+				Name:         s.myself.Name,
+				DisplayName:  s.myself.DisplayName,
+				EmailAddress: s.myself.EmailAddress,
+			}
+		default:
+			is.ticket.Fields.Assignee = &jira.Assignee{AccountID: payload.AccountID, Name: payload.Name, DisplayName: payload.Name + payload.AccountID}
+		}
+		is.updated = time.Now()
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) handleStatuses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, s.statuses)
+}
+
+func (s *Server) handleMyself(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, s.myself)
+}
+
+// view returns the ticket as Jira serves it, with its updated date
+func (is *issue) view() jira.Ticket {
+	ticket := is.ticket
+	ticket.Fields.Updated = is.updated.Format(timeLayout)
+	return ticket
+}
+
+// withIssue runs fn with the issue of the {key} path segment locked, or answers 404
+func (s *Server) withIssue(w http.ResponseWriter, r *http.Request, fn func(*issue)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	is, ok := s.issues[r.PathValue("key")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	fn(is)
+}
+
+// transitionsOf returns the transitions of an issue: explicit ones, or one per status
+func (s *Server) transitionsOf(is *issue) []jira.Transition {
+	if is.transitions != nil {
+		return is.transitions
+	}
+
+	transitions := make([]jira.Transition, 0, len(s.statuses))
+	for i, status := range s.statuses {
+		if status.Name != is.ticket.Fields.Status.Name {
+			transitions = append(transitions, jira.Transition{ID: strconv.Itoa(i + 1), Name: status.Name, To: status})
+		}
+	}
+	return transitions
+}
+
+// page reads startAt and maxResults, capping maxResults to the server page size
+func (s *Server) page(r *http.Request) (int, int) {
+	pageSize := s.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	maxResults, err := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if err != nil || maxResults <= 0 || maxResults > pageSize {
+		maxResults = pageSize
+	}
+	return max(startAt, 0), maxResults
+}
+
+// paginate returns one page of tickets and the total count
+func paginate(tickets []jira.Ticket, startAt int, maxResults int) ([]jira.Ticket, int) {
+	total := len(tickets)
+	if startAt >= total {
+		return []jira.Ticket{}, total
+	}
+	return tickets[startAt:min(startAt+maxResults, total)], total
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError answers like Jira does, with an errorMessages array
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string][]string{"errorMessages": {message}})
+}
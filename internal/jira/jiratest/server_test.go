@@ -0,0 +1,389 @@
+package jiratest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+	"github.com/hyphaene/hexa/internal/outbox"
+	"github.com/spf13/viper"
+)
+
+const (
+	boardID  = 3
+	sprintID = 42
+)
+
+// newSprint starts a fake Jira with an active sprint of n "To Do" tickets, PROJ-0 to PROJ-<n-1>,
+// and isolates the cache and outbox in a temporary home
+func newSprint(t *testing.T, n int) *jiratest.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	jira.SetOffline(false)
+	t.Cleanup(func() { jira.SetOffline(false) })
+
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+	server.Configure()
+	t.Cleanup(func() { viper.Set("jira.auth.type", "") })
+
+	server.AddBoard(boardID, "Team board")
+	server.AddSprint(boardID, jira.Sprint{ID: sprintID, Name: "Sprint 12", State: "active"})
+	for i := range n {
+		server.AddIssue(sprintID, jira.Ticket{
+			Key:    fmt.Sprintf("PROJ-%d", i),
+			Fields: jira.Fields{Summary: fmt.Sprintf("Ticket %d", i), Status: jira.Status{Name: "To Do"}},
+		})
+	}
+	return server
+}
+
+func keysOf(tickets []jira.Ticket) []string {
+	keys := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		keys[i] = ticket.Key
+	}
+	return keys
+}
+
+func TestSprintFetch(t *testing.T) {
+	server := newSprint(t, 130) // Three pages of DefaultPageSize
+	ctx := context.Background()
+
+	boards, err := jira.ListBoards(ctx, "team", 10)
+	if err != nil || len(boards) != 1 || boards[0].ID != boardID {
+		t.Fatalf("ListBoards() = %v, %v", boards, err)
+	}
+
+	activeID, err := jira.GetActiveSprintId(ctx, boardID)
+	if err != nil || activeID != sprintID {
+		t.Fatalf("GetActiveSprintId() = %d, %v, want %d", activeID, err, sprintID)
+	}
+
+	tickets, total, err := jira.FetchSprintTickets(ctx, sprintID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 130 || len(tickets) != 130 {
+		t.Fatalf("FetchSprintTickets() = %d tickets, total %d, want 130", len(tickets), total)
+	}
+	for i, ticket := range tickets {
+		if want := fmt.Sprintf("PROJ-%d", i); ticket.Key != want || ticket.Fields.Updated == "" {
+			t.Fatalf("tickets[%d] = %s (updated %q), want %s with its updated date", i, ticket.Key, ticket.Fields.Updated, want)
+		}
+	}
+
+	var pages int
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, fmt.Sprintf("GET /rest/agile/1.0/sprint/%d/issue", sprintID)) {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("sprint issues fetched in %d request(s), want 3", pages)
+	}
+}
+
+func TestSprintFetchConcurrentPages(t *testing.T) {
+	ctx := context.Background()
+	pagePrefix := func(startAt int) string {
+		return fmt.Sprintf("GET /rest/agile/1.0/sprint/%d/issue?startAt=%d&", sprintID, startAt)
+	}
+
+	// Ten pages of ten tickets for three workers
+	newPagedSprint := func(t *testing.T) *jiratest.Server {
+		server := newSprint(t, 95)
+		server.PageSize = 10
+		viper.Set("jira.concurrency", 3)
+		t.Cleanup(func() { viper.Set("jira.concurrency", nil) })
+		return server
+	}
+
+	t.Run("order kept", func(t *testing.T) {
+		server := newPagedSprint(t)
+
+		tickets, total, err := jira.FetchSprintTickets(ctx, sprintID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 95 || len(tickets) != 95 {
+			t.Fatalf("FetchSprintTickets() = %d tickets, total %d, want 95", len(tickets), total)
+		}
+		for i, ticket := range tickets {
+			if want := fmt.Sprintf("PROJ-%d", i); ticket.Key != want {
+				t.Fatalf("tickets[%d] = %s, want %s", i, ticket.Key, want)
+			}
+		}
+		for page := range 10 {
+			if !slices.ContainsFunc(server.Requests(), func(request string) bool {
+				return strings.HasPrefix(request, pagePrefix(page*10))
+			}) {
+				t.Errorf("page %d never requested", page+1)
+			}
+		}
+	})
+
+	t.Run("failing page", func(t *testing.T) {
+		server := newPagedSprint(t)
+		server.Fail(pagePrefix(50), http.StatusInternalServerError)
+
+		tickets, _, err := jira.FetchSprintTickets(ctx, sprintID)
+		if err == nil || !strings.Contains(err.Error(), "page 6") || !strings.Contains(err.Error(), "500") {
+			t.Fatalf("FetchSprintTickets() error = %v, want the failure of page 6", err)
+		}
+		if tickets != nil {
+			t.Errorf("FetchSprintTickets() = %d tickets, want none with the error", len(tickets))
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	server := newSprint(t, 5)
+	server.AddIssue(sprintID+1, jira.Ticket{Key: "OTHER-1", Fields: jira.Fields{Status: jira.Status{Name: "Blocked"}}})
+	server.UpdateIssue("PROJ-1", func(ticket *jira.Ticket) { ticket.Fields.Status.Name = "In Progress" })
+	server.UpdateIssue("PROJ-2", func(ticket *jira.Ticket) {
+		ticket.Fields.Assignee = &jira.Assignee{AccountID: "test-account", Name: "jdoe"}
+	})
+	server.UpdateIssue("PROJ-3", func(ticket *jira.Ticket) { ticket.Fields.Assignee = &jira.Assignee{Name: "alice"} })
+
+	tests := []struct {
+		jql     string
+		want    []string
+		wantErr bool
+	}{
+		{jql: fmt.Sprintf("sprint = %d", sprintID), want: []string{"PROJ-0", "PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"}},
+		{jql: fmt.Sprintf("sprint = %d AND status = \"In Progress\"", sprintID), want: []string{"PROJ-1"}},
+		{jql: fmt.Sprintf("sprint = %d and status != \"To Do\" ORDER BY Rank ASC", sprintID), want: []string{"PROJ-1"}},
+		{jql: "assignee = currentUser()", want: []string{"PROJ-2"}},
+		{jql: `assignee = "alice"`, want: []string{"PROJ-3"}},
+		{jql: fmt.Sprintf("sprint = %d AND assignee is EMPTY", sprintID), want: []string{"PROJ-0", "PROJ-1", "PROJ-4"}},
+		{jql: "key in (PROJ-4, OTHER-1)", want: []string{"PROJ-4", "OTHER-1"}},
+		{jql: "key = proj-0", want: []string{"PROJ-0"}},
+		{jql: "project = OTHER", want: []string{"OTHER-1"}},
+		{jql: `updated >= "-1h"`, want: []string{"PROJ-0", "PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4", "OTHER-1"}},
+		{jql: "summary ~ login", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jql, func(t *testing.T) {
+			tickets, total, err := jira.SearchIssues(context.Background(), tt.jql, jira.TicketFields, 0)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SearchIssues() = %v, want an error", keysOf(tickets))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := keysOf(tickets); !slices.Equal(got, tt.want) || total != len(tt.want) {
+				t.Errorf("SearchIssues() = %v (total %d), want %v", got, total, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchCloud(t *testing.T) {
+	server := newSprint(t, 120) // Three pages of DefaultPageSize
+	viper.Set("jira.auth.type", jira.AuthCloud)
+	viper.Set("jira.userEmail", "jdoe@example.com")
+	t.Cleanup(func() { viper.Set("jira.userEmail", nil) })
+	ctx := context.Background()
+
+	tickets, total, err := jira.SearchIssues(ctx, fmt.Sprintf("sprint = %d", sprintID), jira.TicketFields, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 120 || total != 120 || tickets[0].Key != "PROJ-0" || tickets[119].Key != "PROJ-119" {
+		t.Errorf("SearchIssues() = %d tickets (total %d), want PROJ-0 to PROJ-119", len(tickets), total)
+	}
+
+	pages := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "GET /rest/api/2/search") {
+			t.Errorf("removed Cloud endpoint called: %s", request)
+		}
+		if strings.HasPrefix(request, "GET /rest/api/3/search/jql") {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("%d search pages requested, want 3", pages)
+	}
+
+	if _, err := jira.FetchSprintTicketsUpdatedSince(ctx, sprintID, time.Now().Add(-time.Hour), 10); !errors.Is(err, jira.ErrTooManyResults) {
+		t.Errorf("FetchSprintTicketsUpdatedSince() error = %v, want %v", err, jira.ErrTooManyResults)
+	}
+}
+
+func TestIncrementalRefresh(t *testing.T) {
+	server := newSprint(t, 120)
+	ctx := context.Background()
+	full := func(ctx context.Context) ([]jira.Ticket, int, error) { return jira.FetchSprintTickets(ctx, sprintID) }
+
+	first, err := cache.RefreshSprint(ctx, sprintID, time.Now(), cache.IncrementalFetcher(nil, full))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first refresh was 20 minutes ago, and the sprint has not changed since then...
+	server.Backdate(time.Hour)
+	first.CachedAt = time.Now().Add(-20 * time.Minute)
+	// ...except for these changes
+	server.UpdateIssue("PROJ-110", func(ticket *jira.Ticket) { ticket.Fields.Status.Name = "Blocked" })
+	server.RemoveIssue(sprintID, "PROJ-5")
+	server.AddIssue(sprintID, jira.Ticket{Key: "PROJ-500", Fields: jira.Fields{Status: jira.Status{Name: "New"}}})
+
+	before := len(server.Requests())
+	second, err := cache.RefreshSprint(ctx, sprintID, time.Now(), cache.IncrementalFetcher(first, full))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, request := range server.Requests()[before:] {
+		if strings.Contains(request, "/rest/agile/1.0/sprint/") {
+			t.Errorf("incremental refresh downloaded the sprint: %s", request)
+		}
+	}
+
+	want, _, err := full(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keysOf(second.Issues), keysOf(want)) || second.Total != len(want) {
+		t.Fatalf("incremental refresh: %d tickets, want the %d of a full fetch", len(second.Issues), len(want))
+	}
+	for i, ticket := range second.Issues {
+		if ticket.Fields.Status != want[i].Fields.Status {
+			t.Errorf("%s: status %q, want %q", ticket.Key, ticket.Fields.Status.Name, want[i].Fields.Status.Name)
+		}
+	}
+}
+
+func TestWrites(t *testing.T) {
+	tests := []struct {
+		name     string
+		authType string
+		op       outbox.Operation
+		check    func(t *testing.T, server *jiratest.Server)
+	}{
+		{
+			name: "transition",
+			op:   outbox.Operation{Type: outbox.OpTransition, Status: "in progress"},
+			check: func(t *testing.T, server *jiratest.Server) {
+				if ticket, _ := server.Issue("PROJ-0"); ticket.Fields.Status.Name != "In Progress" {
+					t.Errorf("status = %q, want In Progress", ticket.Fields.Status.Name)
+				}
+			},
+		},
+		{
+			name: "comment",
+			op:   outbox.Operation{Type: outbox.OpComment, Body: "Ready for review"},
+			check: func(t *testing.T, server *jiratest.Server) {
+				if comments := server.Comments("PROJ-0"); !slices.Equal(comments, []string{"Ready for review"}) {
+					t.Errorf("comments = %v", comments)
+				}
+			},
+		},
+		{
+			name: "labels",
+			op:   outbox.Operation{Type: outbox.OpLabel, Labels: []string{"backend", "urgent"}},
+			check: func(t *testing.T, server *jiratest.Server) {
+				if labels := server.Labels("PROJ-0"); !slices.Equal(labels, []string{"backend", "urgent"}) {
+					t.Errorf("labels = %v", labels)
+				}
+			},
+		},
+		{
+			name: "assign to me by username",
+			op:   outbox.Operation{Type: outbox.OpAssign, Assignee: "jdoe"},
+			check: func(t *testing.T, server *jiratest.Server) {
+				assertAssignedToMe(t, server)
+			},
+		},
+		{
+			name:     "assign to me by account ID on Cloud",
+			authType: jira.AuthCloud,
+			op:       outbox.Operation{Type: outbox.OpAssign, Assignee: "test-account"},
+			check: func(t *testing.T, server *jiratest.Server) {
+				assertAssignedToMe(t, server)
+			},
+		},
+		{
+			name: "assign to someone else",
+			op:   outbox.Operation{Type: outbox.OpAssign, Assignee: "alice"},
+			check: func(t *testing.T, server *jiratest.Server) {
+				ticket, _ := server.Issue("PROJ-0")
+				if ticket.Fields.Assignee == nil || ticket.Fields.Assignee.Name != "alice" {
+					t.Errorf("assignee = %+v, want alice", ticket.Fields.Assignee)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, offline := range []bool{false, true} {
+			name := tt.name
+			if offline {
+				name += " queued then replayed"
+			}
+			t.Run(name, func(t *testing.T) {
+				server := newSprint(t, 1)
+				viper.Set("jira.auth.type", tt.authType)
+				viper.Set("jira.auth.email", "jdoe@example.com")
+				ctx := context.Background()
+
+				op := tt.op
+				op.IssueKey = "PROJ-0"
+				jira.SetOffline(offline)
+				queued, err := outbox.Submit(ctx, &op)
+				if err != nil || queued != offline {
+					t.Fatalf("Submit() = %v, %v, want queued %v", queued, err, offline)
+				}
+
+				if offline {
+					jira.SetOffline(false)
+					ops, err := outbox.List()
+					if err != nil || len(ops) != 1 {
+						t.Fatalf("outbox.List() = %v, %v, want the queued operation", ops, err)
+					}
+					if err := outbox.Replay(ctx, &ops[0], false); err != nil {
+						t.Fatalf("Replay() = %v", err)
+					}
+					if ops, _ := outbox.List(); len(ops) != 0 {
+						t.Errorf("%d operation(s) left in the outbox", len(ops))
+					}
+				}
+				tt.check(t, server)
+			})
+		}
+	}
+}
+
+// assertAssignedToMe checks that PROJ-0 is assigned to the server user
+func assertAssignedToMe(t *testing.T, server *jiratest.Server) {
+	t.Helper()
+	me, err := jira.FetchCurrentUser(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets, _, err := jira.FetchSprintTickets(context.Background(), sprintID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket, _ := server.Issue("PROJ-0")
+	if mine := jira.FilterByAssignee(tickets, "me", *me); len(mine) != 1 {
+		t.Errorf("assignee %+v is not matched as me (%s, %s)", ticket.Fields.Assignee, me.Name, me.AccountID)
+	}
+	// Cloud hides emails: the account ID and username must be set too
+	if assignee := ticket.Fields.Assignee; assignee == nil || assignee.AccountID != me.AccountID || assignee.Name != me.Name {
+		t.Errorf("assignee = %+v, want account %s and username %s", assignee, me.AccountID, me.Name)
+	}
+}
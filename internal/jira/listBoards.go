@@ -0,0 +1,68 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hyphaene/hexa/internal/logger"
+)
+
+// boardPageSize is the number of boards asked per page (the API caps it at 50)
+const boardPageSize = 50
+
+// ListBoards returns the boards whose name contains query (every board if empty), at most limit
+func ListBoards(ctx context.Context, query string, limit int) ([]Board, error) {
+	var boards []Board
+	for startAt := 0; len(boards) < limit; {
+		params := url.Values{}
+		params.Set("startAt", fmt.Sprint(startAt))
+		params.Set("maxResults", fmt.Sprint(boardPageSize))
+		if query != "" {
+			params.Set("name", query)
+		}
+		apiURL := fmt.Sprintf("%s/rest/agile/1.0/board?%s", baseURL(), params.Encode())
+
+		req, err := newRequest(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := do(newClient(), req)
+		if err != nil {
+			return nil, fmt.Errorf("executing request: %w", err)
+		}
+
+		var page BoardListResponse
+		err = func() error {
+			defer func() {
+				if cerr := resp.Body.Close(); cerr != nil {
+					logger.Debug("closing response body", "error", cerr)
+				}
+			}()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("API returned status %d", resp.StatusCode)
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+				return fmt.Errorf("decoding response: %w", err)
+			}
+			return nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		boards = append(boards, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+		startAt += len(page.Values)
+	}
+
+	if len(boards) > limit {
+		boards = boards[:limit]
+	}
+	return boards, nil
+}
@@ -0,0 +1,375 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/credentials"
+	"github.com/hyphaene/hexa/internal/oauth"
+	"github.com/spf13/viper"
+)
+
+// OAuthDirName is the directory holding OAuth tokens (one file per Jira host), under the state
+// directory of the profile (config.StateDir): each profile logs in on its own
+const OAuthDirName = "oauth"
+
+// OAuth providers accepted by jira.oauth.provider
+const (
+	ProviderDataCenter = "datacenter" // Jira Data Center incoming application link
+	ProviderCloud      = "cloud"      // Atlassian Cloud OAuth 2.0 (3LO) app
+)
+
+// DefaultRedirectURL is the callback registered for the OAuth application by default
+const DefaultRedirectURL = "http://127.0.0.1:8976/callback"
+
+// Atlassian Cloud endpoints: 3LO tokens are used against api.atlassian.com, not the site URL
+const (
+	cloudAuthorizeURL = "https://auth.atlassian.com/authorize"
+	cloudTokenURL     = "https://auth.atlassian.com/oauth/token"
+	cloudResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	cloudAPIURL       = "https://api.atlassian.com/ex/jira/"
+)
+
+// Default scopes: refresh tokens require offline_access on Cloud
+var (
+	dataCenterScopes = []string{"WRITE"}
+	cloudScopes      = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+)
+
+// ErrNotLoggedIn is returned when jira.auth.type is oauth and no token is stored
+var ErrNotLoggedIn = errors.New("not logged in to Jira: run 'hexa jira login'")
+
+// OAuthToken is the stored OAuth session of a Jira instance
+type OAuthToken struct {
+	oauth.Token
+	CloudID string `json:"cloud_id,omitempty"` // Atlassian Cloud site the token gives access to
+}
+
+// session caches the stored token: sprint pages are fetched concurrently
+var session struct {
+	mu    sync.Mutex
+	token *OAuthToken
+}
+
+// OAuthProvider returns jira.oauth.provider, guessed from jira.url when not configured
+func OAuthProvider() string {
+	provider := strings.ToLower(strings.TrimSpace(viper.GetString("jira.oauth.provider")))
+	if provider != "" {
+		return provider
+	}
+	if u, err := url.Parse(viper.GetString("jira.url")); err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		return ProviderCloud
+	}
+	return ProviderDataCenter
+}
+
+// OAuthConfig builds the OAuth client from jira.oauth.* (endpoints default to the provider's)
+func OAuthConfig() (*oauth.Config, error) {
+	jiraURL := strings.TrimSuffix(viper.GetString("jira.url"), "/")
+	clientID := viper.GetString("jira.oauth.clientId")
+	if clientID == "" {
+		return nil, fmt.Errorf("jira.oauth.clientId must be configured to log in with OAuth")
+	}
+
+	clientSecret, err := credentials.GetString("jira.oauth.clientSecret")
+	if err != nil {
+		return nil, err
+	}
+
+	// No HTTPClient: token requests carry secrets in their body and are never traced
+	cfg := &oauth.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  DefaultRedirectURL,
+	}
+
+	switch OAuthProvider() {
+	case ProviderDataCenter:
+		if jiraURL == "" {
+			return nil, fmt.Errorf("jira.url must be configured")
+		}
+		cfg.AuthorizeURL = jiraURL + "/rest/oauth2/latest/authorize"
+		cfg.TokenURL = jiraURL + "/rest/oauth2/latest/token"
+		cfg.RevokeURL = jiraURL + "/rest/oauth2/latest/revoke"
+		cfg.Scopes = dataCenterScopes
+	case ProviderCloud:
+		cfg.AuthorizeURL = cloudAuthorizeURL
+		cfg.TokenURL = cloudTokenURL
+		cfg.Scopes = cloudScopes
+		cfg.AuthParams = map[string]string{"audience": "api.atlassian.com", "prompt": "consent"}
+	default:
+		return nil, fmt.Errorf("invalid jira.oauth.provider '%s' (valid: %s, %s)", OAuthProvider(), ProviderDataCenter, ProviderCloud)
+	}
+
+	if value := viper.GetString("jira.oauth.authorizeUrl"); value != "" {
+		cfg.AuthorizeURL = value
+	}
+	if value := viper.GetString("jira.oauth.tokenUrl"); value != "" {
+		cfg.TokenURL = value
+	}
+	if value := viper.GetString("jira.oauth.revokeUrl"); value != "" {
+		cfg.RevokeURL = value
+	}
+	if value := viper.GetString("jira.oauth.redirectUrl"); value != "" {
+		cfg.RedirectURL = value
+	}
+	if scopes := viper.GetStringSlice("jira.oauth.scopes"); len(scopes) > 0 {
+		cfg.Scopes = scopes
+	}
+
+	return cfg, nil
+}
+
+// Login runs the OAuth flow (see oauth.Config.Login) and stores the tokens for jira.url
+func Login(ctx context.Context, open func(authURL string)) (*OAuthToken, error) {
+	cfg, err := OAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := cfg.Login(ctx, open)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &OAuthToken{Token: *token}
+	if OAuthProvider() == ProviderCloud {
+		if stored.CloudID, err = fetchCloudID(ctx, token.AccessToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := saveOAuthToken(stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// Logout revokes the stored tokens (when the provider supports it) and deletes them.
+// The tokens are deleted even if the revocation fails. It returns ErrNotLoggedIn without a session.
+func Logout(ctx context.Context) error {
+	token, err := loadOAuthToken()
+	if err != nil {
+		return err
+	}
+
+	var revokeErr error
+	if cfg, err := OAuthConfig(); err != nil {
+		revokeErr = err
+	} else {
+		if token.RefreshToken != "" {
+			revokeErr = cfg.Revoke(ctx, token.RefreshToken, "refresh_token")
+		}
+		if revokeErr == nil {
+			revokeErr = cfg.Revoke(ctx, token.AccessToken, "access_token")
+		}
+	}
+
+	session.mu.Lock()
+	session.token = nil
+	session.mu.Unlock()
+
+	path, err := oauthTokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("deleting OAuth tokens: %w", err)
+	}
+
+	if revokeErr != nil {
+		return fmt.Errorf("tokens deleted locally, but revocation failed: %w", revokeErr)
+	}
+	return nil
+}
+
+// HasCredentials reports whether Jira calls can be authenticated (a token, or an OAuth session)
+func HasCredentials() bool {
+	if AuthType() != AuthOAuth {
+		return viper.GetString("jira.token") != ""
+	}
+	_, err := loadOAuthToken()
+	return err == nil
+}
+
+// accessToken returns a valid OAuth access token, refreshing it when it expired
+func accessToken(ctx context.Context) (string, error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	token, err := currentSession()
+	if err != nil {
+		return "", err
+	}
+	if token.Valid() {
+		return token.AccessToken, nil
+	}
+
+	if token.RefreshToken == "" {
+		return "", fmt.Errorf("OAuth access token expired: run 'hexa jira login'")
+	}
+	if offline.Load() {
+		return "", ErrOffline
+	}
+
+	cfg, err := OAuthConfig()
+	if err != nil {
+		return "", err
+	}
+	refreshed, err := cfg.Refresh(ctx, token.RefreshToken)
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		return "", ctx.Err()
+	case oauth.IsInvalidGrant(err):
+		return "", fmt.Errorf("OAuth session expired or revoked, run 'hexa jira login': %w", err)
+	case isNetworkError(err):
+		offline.Store(true)
+		return "", fmt.Errorf("%w (%w)", ErrOffline, err)
+	default:
+		return "", fmt.Errorf("refreshing OAuth token: %w", err)
+	}
+
+	updated := &OAuthToken{Token: *refreshed, CloudID: token.CloudID}
+	if err := saveOAuthToken(updated); err != nil {
+		return "", err
+	}
+	session.token = updated
+	return updated.AccessToken, nil
+}
+
+// currentSession returns the cached token, loading it on first use (session.mu must be held)
+func currentSession() (*OAuthToken, error) {
+	if session.token != nil {
+		return session.token, nil
+	}
+	token, err := loadOAuthToken()
+	if err != nil {
+		return nil, err
+	}
+	session.token = token
+	return token, nil
+}
+
+// baseURL returns the root of the Jira REST API: jira.url, or the api.atlassian.com
+// gateway of the site when using a Cloud OAuth session
+func baseURL() string {
+	jiraURL := strings.TrimSuffix(viper.GetString("jira.url"), "/")
+	if AuthType() != AuthOAuth || OAuthProvider() != ProviderCloud {
+		return jiraURL
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if token, err := currentSession(); err == nil && token.CloudID != "" {
+		return cloudAPIURL + token.CloudID
+	}
+	return jiraURL
+}
+
+// fetchCloudID finds the Cloud site matching jira.url among the sites granted to the token
+func fetchCloudID(ctx context.Context, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", cloudResourcesURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := do(newClient(), req)
+	if err != nil {
+		return "", fmt.Errorf("listing accessible Cloud sites: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("listing accessible Cloud sites: status %d", resp.StatusCode)
+	}
+
+	var sites []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&sites); err != nil {
+		return "", fmt.Errorf("decoding accessible Cloud sites: %w", err)
+	}
+
+	jiraURL := strings.TrimSuffix(viper.GetString("jira.url"), "/")
+	for _, site := range sites {
+		if strings.EqualFold(strings.TrimSuffix(site.URL, "/"), jiraURL) {
+			return site.ID, nil
+		}
+	}
+	return "", fmt.Errorf("the OAuth app was not granted access to %s", jiraURL)
+}
+
+// oauthTokenPath returns the token file of the configured Jira instance
+func oauthTokenPath() (string, error) {
+	u, err := url.Parse(viper.GetString("jira.url"))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("jira.url must be configured")
+	}
+
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, OAuthDirName, strings.ReplaceAll(u.Host, ":", "_")+".json"), nil
+}
+
+// loadOAuthToken reads the stored token (ErrNotLoggedIn if there is none)
+func loadOAuthToken() (*OAuthToken, error) {
+	path, err := oauthTokenPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading OAuth tokens: %w", err)
+	}
+
+	var token OAuthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("decoding OAuth tokens %s: %w", path, err)
+	}
+	return &token, nil
+}
+
+// saveOAuthToken writes the token file, readable by the user only
+func saveOAuthToken(token *OAuthToken) error {
+	path, err := oauthTokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating OAuth directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding OAuth tokens: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing OAuth tokens: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing OAuth tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TicketFields are the fields requested by searches, matching the Fields struct
+var TicketFields = []string{"summary", "status", "assignee", "priority"}
+
+// ErrTooManyResults is returned when a search matches more issues than the caller accepts
+var ErrTooManyResults = errors.New("too many results")
+
+// SearchIssues runs a JQL search and returns every matching ticket with the requested fields.
+// If limit > 0 and the search matches more than limit issues, ErrTooManyResults is returned
+// after the first page, without downloading the rest.
+// Jira Cloud removed /rest/api/2/search: its replacement, /rest/api/3/search/jql, is used there.
+func SearchIssues(ctx context.Context, jql string, fields []string, limit int) ([]Ticket, int, error) {
+	pageSize := 100
+	if limit > 0 && limit < pageSize {
+		pageSize = limit + 1 // One more than the limit is enough to detect an overflow
+	}
+
+	if IsCloud() {
+		return searchJQL(ctx, jql, fields, limit, pageSize)
+	}
+
+	jiraURL := baseURL()
+	var tickets []Ticket
+	for startAt := 0; ; {
+		apiURL := fmt.Sprintf("%s/rest/api/2/search?jql=%s&fields=%s&startAt=%d&maxResults=%d",
+			jiraURL, url.QueryEscape(jql), url.QueryEscape(strings.Join(fields, ",")), startAt, pageSize)
+
+		var resp SprintIssuesResponse
+		if err := getJSON(ctx, apiURL, &resp); err != nil {
+			return nil, 0, err
+		}
+		if limit > 0 && resp.Total > limit {
+			return nil, resp.Total, fmt.Errorf("%w: %d issues match, limit is %d", ErrTooManyResults, resp.Total, limit)
+		}
+
+		tickets = append(tickets, resp.Issues...)
+		startAt += len(resp.Issues) // Jira caps maxResults: pages may be smaller than asked
+		if len(resp.Issues) == 0 || startAt >= resp.Total {
+			return tickets, resp.Total, nil
+		}
+	}
+}
+
+// searchJQLResponse is a page of /rest/api/3/search/jql: no total, pages are chained by token
+type searchJQLResponse struct {
+	Issues        []Ticket `json:"issues"`
+	NextPageToken string   `json:"nextPageToken"`
+	IsLast        bool     `json:"isLast"`
+}
+
+// searchJQL is SearchIssues on Jira Cloud. Without a total in the response, an overflow of
+// limit is only detected once limit+1 issues are downloaded, and the total returned is then
+// that count rather than the number of matching issues.
+func searchJQL(ctx context.Context, jql string, fields []string, limit int, pageSize int) ([]Ticket, int, error) {
+	jiraURL := baseURL()
+	var tickets []Ticket
+	for pageToken := ""; ; {
+		apiURL := fmt.Sprintf("%s/rest/api/3/search/jql?jql=%s&fields=%s&maxResults=%d",
+			jiraURL, url.QueryEscape(jql), url.QueryEscape(strings.Join(fields, ",")), pageSize)
+		if pageToken != "" {
+			apiURL += "&nextPageToken=" + url.QueryEscape(pageToken)
+		}
+
+		var resp searchJQLResponse
+		if err := getJSON(ctx, apiURL, &resp); err != nil {
+			return nil, 0, err
+		}
+
+		tickets = append(tickets, resp.Issues...)
+		if limit > 0 && len(tickets) > limit {
+			return nil, len(tickets), fmt.Errorf("%w: more than %d issues match", ErrTooManyResults, limit)
+		}
+		if resp.IsLast || resp.NextPageToken == "" || len(resp.Issues) == 0 {
+			return tickets, len(tickets), nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// FetchSprintTicketsUpdatedSince returns the sprint tickets updated since the given time.
+// The JQL uses a relative date ("-15m") so it does not depend on the Jira user timezone.
+func FetchSprintTicketsUpdatedSince(ctx context.Context, sprintID int, since time.Time, limit int) ([]Ticket, error) {
+	// One extra minute of margin: JQL dates have minute precision
+	minutes := int(math.Ceil(time.Since(since).Minutes())) + 1
+	jql := fmt.Sprintf(`sprint = %d AND updated >= "-%dm"`, sprintID, minutes)
+
+	tickets, _, err := SearchIssues(ctx, jql, TicketFields, limit)
+	return tickets, err
+}
+
+// FetchSprintIssueKeys lists the keys of every ticket in a sprint (cheap: no fields downloaded)
+func FetchSprintIssueKeys(ctx context.Context, sprintID int) ([]string, error) {
+	tickets, _, err := SearchIssues(ctx, fmt.Sprintf("sprint = %d", sprintID), []string{"key"}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		keys = append(keys, ticket.Key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,49 @@
+package jira_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+)
+
+func TestFetchSprintIssueKeysPaginates(t *testing.T) {
+	tests := []struct {
+		name     string
+		issues   int
+		pageSize int // Server cap on maxResults
+	}{
+		{"single page", 30, 0},
+		{"capped by the server", 130, jiratest.DefaultPageSize},
+		{"uneven pages", 101, 7},
+		{"empty sprint", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jira.SetOffline(false)
+			server := jiratest.NewServer()
+			defer server.Close()
+			server.PageSize = tt.pageSize
+			server.Configure()
+			for i := range tt.issues {
+				server.AddIssue(1, jira.Ticket{Key: fmt.Sprintf("PROJ-%d", i)})
+			}
+
+			keys, err := jira.FetchSprintIssueKeys(context.Background(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != tt.issues {
+				t.Fatalf("got %d keys, want %d", len(keys), tt.issues)
+			}
+			for i, key := range keys {
+				if want := fmt.Sprintf("PROJ-%d", i); key != want {
+					t.Fatalf("keys[%d] = %s, want %s", i, key, want)
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package jira
 
+import "time"
+
+// updatedLayout is the format of Jira dates, e.g. "2024-03-01T14:05:09.000+0100"
+const updatedLayout = "2006-01-02T15:04:05.000-0700"
+
 // Ticket represents a single Jira issue with relevant fields for display and filtering
 type Ticket struct {
 	Key    string `json:"key"` // e.g., "PROJ-123"
@@ -10,8 +15,18 @@ type Ticket struct {
 type Fields struct {
 	Summary  string    `json:"summary"`
 	Status   Status    `json:"status"`
-	Assignee *Assignee `json:"assignee"` // Pointer: null when unassigned
-	Priority *Priority `json:"priority"` // Pointer: null when no priority set
+	Assignee *Assignee `json:"assignee"`          // Pointer: null when unassigned
+	Priority *Priority `json:"priority"`          // Pointer: null when no priority set
+	Updated  string    `json:"updated,omitempty"` // Last change, e.g., "2024-03-01T14:05:09.000+0100"
+}
+
+// UpdatedAt returns the date of the last change of the ticket (false if unknown)
+func (f Fields) UpdatedAt() (time.Time, bool) {
+	updated, err := time.Parse(updatedLayout, f.Updated)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return updated, true
 }
 
 // Status represents the workflow status of a ticket
@@ -21,8 +36,10 @@ type Status struct {
 
 // Assignee represents the user assigned to a ticket
 type Assignee struct {
+	AccountID    string `json:"accountId"`    // Jira Cloud account ID, always present on Cloud
+	Name         string `json:"name"`         // Data Center username
 	DisplayName  string `json:"displayName"`  // e.g., "John Doe"
-	EmailAddress string `json:"emailAddress"` // e.g., "john.doe@example.com"
+	EmailAddress string `json:"emailAddress"` // e.g., "john.doe@example.com" (hidden by Cloud privacy settings)
 }
 
 // Priority represents the priority level of a ticket
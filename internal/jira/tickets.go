@@ -1,12 +1,15 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/hyphaene/hexa/internal/logger"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/viper"
 )
@@ -20,100 +23,163 @@ type SprintIssuesResponse struct {
 	Issues     []Ticket `json:"issues"`
 }
 
-// FetchSprintTickets fetches all tickets from a sprint with pagination
-func FetchSprintTickets(sprintID int) ([]Ticket, int, error) {
-	jiraToken := viper.GetString("jira.token")
-	jiraURL := viper.GetString("jira.url")
+// DefaultConcurrency is the number of sprint pages fetched in parallel when jira.concurrency is unset
+const DefaultConcurrency = 4
 
-	if jiraToken == "" || jiraURL == "" {
+// FetchSprintTickets fetches all tickets from a sprint with pagination.
+// The first page gives the total; the remaining pages are fetched by a bounded
+// worker pool (jira.concurrency) and reassembled in order.
+func FetchSprintTickets(ctx context.Context, sprintID int) ([]Ticket, int, error) {
+	jiraURL := baseURL()
+
+	if !HasCredentials() || jiraURL == "" {
 		return nil, 0, fmt.Errorf("jira.token and jira.url must be configured")
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	client := newClient()
+
+	pageURL := func(startAt, maxResults int) string {
+		return fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?startAt=%d&maxResults=%d",
+			jiraURL, sprintID, startAt, maxResults)
 	}
 
-	var allTickets []Ticket
-	startAt := 0
-	maxResults := 100
-	totalCount := 0
+	// First page: gives the total and the page size actually applied by Jira
+	first, err := fetchSprintPage(ctx, client, 1, pageURL(0, 100))
+	if err != nil {
+		return nil, 0, err
+	}
 
 	var bar *progressbar.ProgressBar
+	if first.Total > 0 {
+		progress := logger.Progress()
+		bar = progressbar.NewOptions(first.Total,
+			progressbar.OptionSetWriter(progress),
+			progressbar.OptionSetDescription("📥 Fetching tickets"),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetWidth(40),
+			progressbar.OptionThrottle(65*time.Millisecond),
+			progressbar.OptionShowIts(),
+			progressbar.OptionSetItsString("tickets"),
+			progressbar.OptionOnCompletion(func() {
+				_, _ = fmt.Fprint(progress, "\n")
+			}),
+		)
+		_ = bar.Add(len(first.Issues))
+	}
 
-	for page := 1; ; page++ {
-		url := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?startAt=%d&maxResults=%d",
-			jiraURL, sprintID, startAt, maxResults)
-
-		// Show API call info
-		fmt.Fprintf(os.Stderr, "🌐 [API Call %d] GET %s\n", page, url)
+	var received atomic.Int64
+	received.Add(int64(len(first.Issues)))
+	logger.Debug("Sprint page received", "page", 1, "tickets", len(first.Issues), "received", len(first.Issues), "total", first.Total)
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, 0, fmt.Errorf("creating request: %w", err)
+	pageSize := first.MaxResults
+	if pageSize <= 0 {
+		pageSize = len(first.Issues)
+	}
+	if first.IsLast || pageSize == 0 || len(first.Issues) >= first.Total {
+		if bar != nil {
+			_ = bar.Finish()
 		}
+		return first.Issues, first.Total, nil
+	}
 
-		req.Header.Add("Accept", "application/json")
-		req.Header.Add("Authorization", "Bearer "+jiraToken)
+	// Remaining pages, one slot per page so that results keep the sprint order
+	pageCount := (first.Total + pageSize - 1) / pageSize
+	pages := make([][]Ticket, pageCount)
+	pages[0] = first.Issues
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		firstErr error
+	)
+	jobs := make(chan int)
+	for w := 0; w < min(concurrency(), pageCount-1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				resp, err := fetchSprintPage(ctx, client, page+1, pageURL(page*pageSize, pageSize))
+				if err != nil {
+					// Keep the first failure and cancel the pages still in flight
+					failOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+
+				pages[page] = resp.Issues
+				if bar != nil {
+					_ = bar.Add(len(resp.Issues))
+				}
+				logger.Debug("Sprint page received", "page", page+1, "tickets", len(resp.Issues),
+					"received", received.Add(int64(len(resp.Issues))), "total", first.Total)
+			}
+		}()
+	}
 
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, 0, fmt.Errorf("calling Jira API: %w", err)
+feed:
+	for page := 1; page < pageCount; page++ {
+		select {
+		case jobs <- page:
+		case <-ctx.Done():
+			break feed
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		if resp.StatusCode != http.StatusOK {
-			_ = resp.Body.Close()
-			return nil, 0, fmt.Errorf("jira API returned status %d", resp.StatusCode)
-		}
+	if firstErr != nil {
+		return nil, 0, firstErr
+	}
 
-		var sprintResp SprintIssuesResponse
-		if err := json.NewDecoder(resp.Body).Decode(&sprintResp); err != nil {
-			_ = resp.Body.Close()
-			return nil, 0, fmt.Errorf("decoding response: %w", err)
-		}
-		_ = resp.Body.Close()
-
-		// Initialize progress bar after first response
-		if bar == nil && sprintResp.Total > 0 {
-			bar = progressbar.NewOptions(sprintResp.Total,
-				progressbar.OptionSetWriter(os.Stderr),
-				progressbar.OptionSetDescription("📥 Fetching tickets"),
-				progressbar.OptionShowCount(),
-				progressbar.OptionSetWidth(40),
-				progressbar.OptionThrottle(65*time.Millisecond),
-				progressbar.OptionShowIts(),
-				progressbar.OptionSetItsString("tickets"),
-				progressbar.OptionOnCompletion(func() {
-					fmt.Fprint(os.Stderr, "\n")
-				}),
-			)
-		}
+	allTickets := make([]Ticket, 0, first.Total)
+	for _, issues := range pages {
+		allTickets = append(allTickets, issues...)
+	}
 
-		// Update progress
-		if bar != nil {
-			_ = bar.Add(len(sprintResp.Issues))
-		}
+	// Ensure progress bar completes
+	if bar != nil {
+		_ = bar.Finish()
+	}
 
-		allTickets = append(allTickets, sprintResp.Issues...)
-		totalCount = sprintResp.Total
+	return allTickets, first.Total, nil
+}
 
-		fmt.Fprintf(os.Stderr, "✅ [Page %d] Received %d tickets (total: %d/%d)\n",
-			page, len(sprintResp.Issues), len(allTickets), totalCount)
+// fetchSprintPage fetches and decodes one page of sprint tickets
+func fetchSprintPage(ctx context.Context, client *http.Client, page int, url string) (*SprintIssuesResponse, error) {
+	req, err := newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		// Stop if no more tickets or explicitly marked as last page
-		if sprintResp.IsLast || len(sprintResp.Issues) == 0 {
-			break
-		}
+	resp, err := do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("calling Jira API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
 
-		startAt += maxResults
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("page %d: jira API returned status %d", page, resp.StatusCode)
 	}
 
-	// Ensure progress bar completes
-	if bar != nil {
-		_ = bar.Finish()
+	var sprintResp SprintIssuesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sprintResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
 	}
 
-	return allTickets, totalCount, nil
+	return &sprintResp, nil
+}
+
+// concurrency returns how many sprint pages may be fetched in parallel (jira.concurrency)
+func concurrency() int {
+	if !viper.IsSet("jira.concurrency") {
+		return DefaultConcurrency
+	}
+	return max(viper.GetInt("jira.concurrency"), 1)
 }
 
 // FilterByStatus filters tickets by exact status name
@@ -128,8 +194,8 @@ func FilterByStatus(tickets []Ticket, statusName string) []Ticket {
 }
 
 // FilterByAssignee filters tickets by assignee
-// filter can be: "me" (match userEmail), "unassigned" (nil assignee), "all" (no filtering)
-func FilterByAssignee(tickets []Ticket, filter string, userEmail string) []Ticket {
+// filter can be: "me" (match the user, see UserProfile.Matches), "unassigned" (nil assignee), "all" (no filtering)
+func FilterByAssignee(tickets []Ticket, filter string, me UserProfile) []Ticket {
 	if filter == "all" {
 		return tickets
 	}
@@ -138,7 +204,7 @@ func FilterByAssignee(tickets []Ticket, filter string, userEmail string) []Ticke
 	for _, ticket := range tickets {
 		switch filter {
 		case "me":
-			if ticket.Fields.Assignee != nil && ticket.Fields.Assignee.EmailAddress == userEmail {
+			if me.Matches(ticket.Fields.Assignee) {
 				filtered = append(filtered, ticket)
 			}
 		case "unassigned":
@@ -0,0 +1,175 @@
+package jira
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/har"
+	"github.com/hyphaene/hexa/internal/logger"
+)
+
+// Redacted replaces the value of sensitive headers in traces
+const Redacted = "REDACTED"
+
+// sensitiveHeaders are never written to traces
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// Tracer is an http.RoundTripper recording every exchange with Jira (see --trace)
+type Tracer struct {
+	next    http.RoundTripper
+	mu      sync.Mutex
+	entries []har.Entry
+}
+
+// NewTracer wraps a transport; a nil transport means http.DefaultTransport
+func NewTracer(next http.RoundTripper) *Tracer {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Tracer{next: next}
+}
+
+// RoundTrip sends the request, records it with its response and logs a summary line
+func (t *Tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			_ = body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	wait := time.Since(start)
+
+	entry := har.Entry{
+		StartedDateTime: start.Format("2006-01-02T15:04:05.000Z07:00"),
+		Request:         traceRequest(req, reqBody),
+		Response:        har.Response{HTTPVersion: "HTTP/1.1", Headers: []har.NameValue{}, Cookies: []har.NameValue{}, HeadersSize: -1, BodySize: -1},
+	}
+
+	if err != nil {
+		entry.Comment = err.Error()
+		entry.Time = millis(wait)
+		entry.Timings = har.Timings{Send: 0, Wait: millis(wait), Receive: 0}
+		t.record(entry)
+		logger.Info("🛰️  "+req.Method+" "+req.URL.String(), "error", err, "duration", wait.Round(time.Millisecond))
+		return nil, err
+	}
+
+	// Buffer the body so that it can be both recorded and read by the caller
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	total := time.Since(start)
+
+	entry.Response = traceResponse(resp, respBody)
+	entry.Time = millis(total)
+	entry.Timings = har.Timings{Send: 0, Wait: millis(wait), Receive: millis(total - wait)}
+	if readErr != nil {
+		entry.Comment = readErr.Error()
+		t.record(entry)
+		logger.Info("🛰️  "+req.Method+" "+req.URL.String(), "status", resp.StatusCode, "error", readErr, "duration", total.Round(time.Millisecond))
+		return nil, readErr // A RoundTripper returns a response or an error, never both
+	}
+	t.record(entry)
+
+	logger.Info("🛰️  "+req.Method+" "+req.URL.String(), "status", resp.StatusCode, "bytes", len(respBody), "duration", total.Round(time.Millisecond))
+	if len(reqBody) > 0 {
+		logger.Info("   request body", "body", string(reqBody))
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// HAR returns the recorded exchanges as a HAR file
+func (t *Tracer) HAR(creatorVersion string) *har.File {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file := har.New(creatorVersion)
+	file.Log.Entries = append(file.Log.Entries, t.entries...)
+	return file
+}
+
+func (t *Tracer) record(entry har.Entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, entry)
+}
+
+// traceRequest converts a request to its HAR form, sensitive headers redacted
+func traceRequest(req *http.Request, body []byte) har.Request {
+	query := []har.NameValue{}
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			query = append(query, har.NameValue{Name: name, Value: value})
+		}
+	}
+
+	traced := har.Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     traceHeaders(req.Header),
+		QueryString: query,
+		Cookies:     []har.NameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if len(body) > 0 {
+		traced.PostData = &har.PostData{MimeType: req.Header.Get("Content-Type"), Text: string(body)}
+	}
+
+	return traced
+}
+
+// traceResponse converts a response to its HAR form, sensitive headers redacted
+func traceResponse(resp *http.Response, body []byte) har.Response {
+	return har.Response{
+		Status:      resp.StatusCode,
+		StatusText:  strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" "),
+		HTTPVersion: resp.Proto,
+		Headers:     traceHeaders(resp.Header),
+		Cookies:     []har.NameValue{},
+		Content: har.Content{
+			Size:     len(body),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     string(body),
+		},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+// traceHeaders flattens headers, replacing sensitive values with Redacted
+func traceHeaders(header http.Header) []har.NameValue {
+	headers := make([]har.NameValue, 0, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = Redacted
+			}
+			headers = append(headers, har.NameValue{Name: name, Value: value})
+		}
+	}
+	sort.SliceStable(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	return headers
+}
+
+// millis converts a duration to HAR milliseconds
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// failingBody fails after returning part of the body, like a connection reset mid-response
+type failingBody struct{ read bool }
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	if b.read {
+		return 0, errors.New("connection reset by peer")
+	}
+	b.read = true
+	return copy(p, `{"partial":`), nil
+}
+
+func (b *failingBody) Close() error { return nil }
+
+func TestTracerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     io.ReadCloser
+		sendErr  error
+		wantBody string
+		wantErr  bool
+	}{
+		{name: "response", body: io.NopCloser(strings.NewReader(`{"ok":true}`)), wantBody: `{"ok":true}`},
+		{name: "body read failure", body: &failingBody{}, wantErr: true},
+		{name: "send failure", sendErr: errors.New("dial tcp: connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := NewTracer(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if tt.sendErr != nil {
+					return nil, tt.sendErr
+				}
+				return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Proto: "HTTP/1.1", Header: http.Header{}, Body: tt.body}, nil
+			}))
+
+			req, _ := http.NewRequest("GET", "https://jira.example.com/rest/api/2/myself", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			resp, err := tracer.RoundTrip(req)
+
+			if tt.wantErr {
+				if err == nil || resp != nil {
+					t.Fatalf("RoundTrip() = %v, %v, want only an error", resp, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body = %q, want %q", body, tt.wantBody)
+				}
+			}
+
+			entries := tracer.HAR("test").Log.Entries
+			if len(entries) != 1 {
+				t.Fatalf("recorded %d entries, want 1", len(entries))
+			}
+			for _, header := range entries[0].Request.Headers {
+				if header.Name == "Authorization" && header.Value != Redacted {
+					t.Errorf("Authorization recorded as %q", header.Value)
+				}
+			}
+		})
+	}
+}
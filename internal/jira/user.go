@@ -1,9 +1,11 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -11,30 +13,27 @@ import (
 // UserProfile represents the authenticated Jira user
 type UserProfile struct {
 	AccountID    string `json:"accountId"`    // Unique Jira account ID
+	Name         string `json:"name"`         // Username (used to assign tickets)
 	EmailAddress string `json:"emailAddress"` // User's email (used for "me" filter)
 	DisplayName  string `json:"displayName"`  // Full name for display
 }
 
 // FetchCurrentUser fetches the authenticated user's profile from Jira API
-func FetchCurrentUser() (*UserProfile, error) {
-	jiraToken := viper.GetString("jira.token")
-	jiraURL := viper.GetString("jira.url")
+func FetchCurrentUser(ctx context.Context) (*UserProfile, error) {
+	jiraURL := baseURL()
 
-	if jiraToken == "" || jiraURL == "" {
+	if !HasCredentials() || jiraURL == "" {
 		return nil, fmt.Errorf("jira.token and jira.url must be configured")
 	}
 
 	url := fmt.Sprintf("%s/rest/api/latest/myself", jiraURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := newRequest(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", "Bearer "+jiraToken)
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := do(newClient(), req)
 	if err != nil {
 		return nil, fmt.Errorf("calling Jira API: %w", err)
 	}
@@ -52,9 +51,54 @@ func FetchCurrentUser() (*UserProfile, error) {
 	return &profile, nil
 }
 
-// SaveUserEmail persists the user's email to Viper config
-func SaveUserEmail(email string) error {
-	viper.Set("jira.userEmail", email)
+// Matches reports whether a ticket assignee is this user: same account ID (Cloud),
+// same email (when not hidden by privacy settings) or same username (Data Center)
+func (p UserProfile) Matches(assignee *Assignee) bool {
+	if assignee == nil {
+		return false
+	}
+
+	switch {
+	case p.AccountID != "" && assignee.AccountID == p.AccountID:
+		return true
+	case p.EmailAddress != "" && strings.EqualFold(assignee.EmailAddress, p.EmailAddress):
+		return true
+	default:
+		return p.Name != "" && assignee.Name == p.Name
+	}
+}
+
+// IsZero reports whether the profile identifies nobody
+func (p UserProfile) IsZero() bool {
+	return p.AccountID == "" && p.Name == "" && p.EmailAddress == ""
+}
+
+// IsComplete reports whether the profile can match assignees without asking Jira. Jira Cloud
+// hides the email of most users, so an account ID is needed there.
+func (p UserProfile) IsComplete() bool {
+	if IsCloud() {
+		return p.AccountID != ""
+	}
+	return !p.IsZero()
+}
+
+// ConfiguredUser returns the user identity saved in config (jira.userEmail, jira.accountId),
+// to be completed from Jira (FetchCurrentUser) unless IsComplete
+func ConfiguredUser() UserProfile {
+	return UserProfile{
+		AccountID:    viper.GetString("jira.accountId"),
+		EmailAddress: viper.GetString("jira.userEmail"),
+	}
+}
+
+// SaveUser persists the user's email and account ID to Viper config
+func SaveUser(profile *UserProfile) error {
+	if profile.EmailAddress != "" {
+		viper.Set("jira.userEmail", profile.EmailAddress)
+	}
+	if profile.AccountID != "" {
+		viper.Set("jira.accountId", profile.AccountID)
+	}
 
 	// Write to config file if one is being used
 	if viper.ConfigFileUsed() != "" {
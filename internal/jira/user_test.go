@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// setAuthConfig sets the given jira keys for the test
+func setAuthConfig(t *testing.T, values map[string]string) {
+	t.Helper()
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	t.Cleanup(func() {
+		for key := range values {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+func TestUserProfileMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  UserProfile
+		assignee *Assignee
+		want     bool
+	}{
+		{"unassigned", UserProfile{AccountID: "acc-1"}, nil, false},
+		{"same account ID", UserProfile{AccountID: "acc-1"}, &Assignee{AccountID: "acc-1"}, true},
+		{"other account ID", UserProfile{AccountID: "acc-1"}, &Assignee{AccountID: "acc-2"}, false},
+		{"same email, any case", UserProfile{EmailAddress: "Me@Example.com"}, &Assignee{EmailAddress: "me@example.com"}, true},
+		{"email hidden by Jira Cloud", UserProfile{EmailAddress: "me@example.com"}, &Assignee{AccountID: "acc-1"}, false},
+		{"email or account ID", UserProfile{AccountID: "acc-1", EmailAddress: "me@example.com"}, &Assignee{AccountID: "acc-1"}, true},
+		{"same username", UserProfile{Name: "jdoe"}, &Assignee{Name: "jdoe"}, true},
+		{"empty profile", UserProfile{}, &Assignee{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.Matches(tt.assignee); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserProfileIsComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		authType string
+		profile  UserProfile
+		want     bool
+	}{
+		{"data center email", AuthPAT, UserProfile{EmailAddress: "me@example.com"}, true},
+		{"data center empty", AuthBasic, UserProfile{}, false},
+		{"cloud email only", AuthCloud, UserProfile{EmailAddress: "me@example.com"}, false},
+		{"cloud account ID", AuthCloud, UserProfile{AccountID: "acc-1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setAuthConfig(t, map[string]string{"jira.auth.type": tt.authType})
+			if got := tt.profile.IsComplete(); got != tt.want {
+				t.Errorf("IsComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
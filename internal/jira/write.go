@@ -0,0 +1,90 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// FindTransition returns the transition leading to status, case-insensitively
+func FindTransition(transitions []Transition, status string) (*Transition, error) {
+	names := make([]string, 0, len(transitions))
+	for i, t := range transitions {
+		if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+			return &transitions[i], nil
+		}
+		names = append(names, t.To.Name)
+	}
+
+	return nil, fmt.Errorf("no transition to '%s' available (available: %s)", status, strings.Join(names, ", "))
+}
+
+// ApplyTransition executes a transition by ID
+func ApplyTransition(ctx context.Context, issueKey string, transitionID string) error {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", baseURL(), url.PathEscape(issueKey))
+	payload := map[string]any{"transition": map[string]string{"id": transitionID}}
+
+	return sendJSON(ctx, "POST", apiURL, payload)
+}
+
+// AddComment adds a comment to a ticket
+func AddComment(ctx context.Context, issueKey string, body string) error {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s/comment", baseURL(), url.PathEscape(issueKey))
+
+	return sendJSON(ctx, "POST", apiURL, map[string]string{"body": body})
+}
+
+// AssignIssue assigns a ticket to a user (Jira username, or account ID on Jira Cloud)
+func AssignIssue(ctx context.Context, issueKey string, assignee string) error {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s/assignee", baseURL(), url.PathEscape(issueKey))
+
+	if IsCloud() {
+		return sendJSON(ctx, "PUT", apiURL, map[string]string{"accountId": assignee})
+	}
+	return sendJSON(ctx, "PUT", apiURL, map[string]string{"name": assignee})
+}
+
+// AddLabels adds labels to a ticket, keeping the existing ones
+func AddLabels(ctx context.Context, issueKey string, labels []string) error {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s", baseURL(), url.PathEscape(issueKey))
+
+	ops := make([]map[string]string, 0, len(labels))
+	for _, label := range labels {
+		ops = append(ops, map[string]string{"add": label})
+	}
+	payload := map[string]any{"update": map[string]any{"labels": ops}}
+
+	return sendJSON(ctx, "PUT", apiURL, payload)
+}
+
+// sendJSON executes a write request with a JSON payload
+func sendJSON(ctx context.Context, method string, apiURL string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+
+	req, err := newRequest(ctx, method, apiURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := do(newClient(), req)
+	if err != nil {
+		return fmt.Errorf("calling Jira API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Jira explains rejected writes in the body (errorMessages/errors)
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("jira API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// consoleHandler writes human-readable records on one line, prefixed like the rest of hexa output:
+//
+//	🔍 [DEBUG] Cache found age=12s
+//	⚠️  jira.token is not configured
+type consoleHandler struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string // Group prefix for attribute keys
+}
+
+func newConsoleHandler(out io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{mu: &sync.Mutex{}, out: out, level: level}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return lvl >= h.level.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString("❌ ")
+	case record.Level >= slog.LevelWarn:
+		line.WriteString("⚠️  ")
+	case record.Level < slog.LevelInfo:
+		line.WriteString("🔍 [DEBUG] ")
+	}
+	line.WriteString(record.Message)
+
+	for _, attr := range h.attrs {
+		writeAttr(&line, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&line, h.prefix, attr)
+		return true
+	})
+	line.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, line.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, attr := range attrs {
+		attr.Key = h.prefix + attr.Key
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// writeAttr appends " key=value", flattening groups
+func writeAttr(line *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, sub := range attr.Value.Group() {
+			writeAttr(line, prefix+attr.Key+".", sub)
+		}
+		return
+	}
+	_, _ = fmt.Fprintf(line, " %s%s=%v", prefix, attr.Key, attr.Value.Any())
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Options configures the logger, from --log-level/--verbose/--quiet and the log.* config keys
+type Options struct {
+	Level   string // debug, info, warn or error (overrides Verbose and Quiet)
+	Verbose bool   // Shortcut for the debug level
+	Quiet   bool   // Shortcut for the error level, also hides progress bars
+	File    string // Optional JSON log file, recording every level
+}
+
+var (
+	level = new(slog.LevelVar) // Console level, info by default
+
+	mu       sync.Mutex
+	logger   = slog.New(newConsoleHandler(os.Stderr, level))
+	logFile  *os.File
+	progress io.Writer = os.Stderr
+)
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level '%s' (valid: debug, info, warn, error)", name)
+	}
+}
+
+// Configure applies the options to the global logger. It may be called again
+// once the configuration is loaded; a previously opened log file is closed.
+func Configure(opts Options) error {
+	lvl := slog.LevelInfo
+	switch {
+	case opts.Level != "":
+		parsed, err := ParseLevel(opts.Level)
+		if err != nil {
+			return err
+		}
+		lvl = parsed
+	case opts.Verbose:
+		lvl = slog.LevelDebug
+	case opts.Quiet:
+		lvl = slog.LevelError
+	}
+	level.Set(lvl)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	progress = os.Stderr
+	if lvl > slog.LevelInfo {
+		progress = io.Discard
+	}
+
+	closeFile()
+	var handler slog.Handler = newConsoleHandler(os.Stderr, level)
+	if opts.File != "" {
+		if rest, ok := strings.CutPrefix(opts.File, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				opts.File = filepath.Join(home, rest)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0755); err != nil {
+			return fmt.Errorf("creating log directory: %w", err)
+		}
+		file, err := os.OpenFile(opts.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("opening log file: %w", err)
+		}
+		logFile = file
+		handler = multiHandler{handler, slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})}
+	}
+	logger = slog.New(handler)
+
+	return nil
+}
+
+// Close flushes and closes the log file, if any
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	closeFile()
+}
+
+func closeFile() {
+	if logFile != nil {
+		_ = logFile.Close()
+		logFile = nil
+	}
+}
+
+// L returns the global logger
+func L() *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	return logger
+}
+
+// Progress returns the writer for progress bars: stderr, or io.Discard above the info level
+func Progress() io.Writer {
+	mu.Lock()
+	defer mu.Unlock()
+	return progress
+}
+
+// DisableProgress hides progress bars for the rest of the command (e.g., machine-readable output)
+func DisableProgress() {
+	mu.Lock()
+	defer mu.Unlock()
+	progress = io.Discard
+}
+
+// Debug logs at debug level (shown with --verbose or --log-level debug)
+func Debug(msg string, args ...any) {
+	L().Debug(msg, args...)
+}
+
+// Info logs at info level
+func Info(msg string, args ...any) {
+	L().Info(msg, args...)
+}
+
+// Warn logs at warn level
+func Warn(msg string, args ...any) {
+	L().Warn(msg, args...)
+}
+
+// Error logs at error level
+func Error(msg string, args ...any) {
+	L().Error(msg, args...)
+}
+
+// multiHandler sends every record to several handlers (console and log file)
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	for _, h := range m {
+		if h.Enabled(ctx, record.Level) {
+			if err := h.Handle(ctx, record.Clone()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, 0, len(m))
+	for _, h := range m {
+		handlers = append(handlers, h.WithAttrs(attrs))
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, 0, len(m))
+	for _, h := range m {
+		handlers = append(handlers, h.WithGroup(name))
+	}
+	return handlers
+}
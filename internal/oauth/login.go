@@ -0,0 +1,115 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// callbackPage is shown in the browser once the authorization code is received
+const callbackPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>hexa</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 4em">
+<h2>%s</h2><p>%s</p>
+</body></html>`
+
+// callbackResult is what the localhost listener received
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login runs the authorization-code flow with PKCE: it listens on RedirectURL,
+// calls open with the authorization URL, waits for the callback with the right state
+// (or ctx) and exchanges the code for tokens.
+func (c *Config) Login(ctx context.Context, open func(authURL string)) (*Token, error) {
+	redirect, err := url.Parse(c.RedirectURL)
+	if err != nil || redirect.Scheme != "http" || redirect.Host == "" {
+		return nil, fmt.Errorf("invalid redirect URL '%s': expected http://127.0.0.1:<port>/<path>", c.RedirectURL)
+	}
+
+	verifier, err := NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s for the OAuth callback: %w", redirect.Host, err)
+	}
+
+	path := redirect.Path
+	if path == "" {
+		path = "/"
+	}
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		result := readCallback(r.URL.Query(), state)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if errors.Is(result.err, errStateMismatch) {
+			// Not the answer to this login (stale tab, forged request): keep waiting for it
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, callbackPage, "⚠️ Requête ignorée", html.EscapeString(result.err.Error()))
+			return
+		}
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, callbackPage, "❌ Échec de la connexion", html.EscapeString(result.err.Error()))
+		} else {
+			_, _ = fmt.Fprintf(w, callbackPage, "✅ Connexion réussie", "Vous pouvez fermer cet onglet et revenir au terminal.")
+		}
+		select {
+		case results <- result:
+		default: // Only the first callback counts
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	open(c.AuthCodeURL(state, Challenge(verifier)))
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the OAuth callback: %w", ctx.Err())
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return c.Exchange(ctx, result.code, verifier)
+	}
+}
+
+// errStateMismatch marks a callback that does not answer this login: it is ignored
+var errStateMismatch = errors.New("state mismatch in OAuth callback")
+
+// readCallback extracts the authorization code, checking the state against CSRF first, so that
+// a forged callback (even an error one) cannot end the login
+func readCallback(query url.Values, state string) callbackResult {
+	if query.Get("state") != state {
+		return callbackResult{err: errStateMismatch}
+	}
+	if code := query.Get("error"); code != "" {
+		return callbackResult{err: &Error{Code: code, Description: query.Get("error_description")}}
+	}
+	code := query.Get("code")
+	if code == "" {
+		return callbackResult{err: errors.New("no authorization code in OAuth callback")}
+	}
+	return callbackResult{code: code}
+}
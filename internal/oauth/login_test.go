@@ -0,0 +1,132 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestReadCallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantCode  string
+		wantErr   bool
+		wantState bool // The error is errStateMismatch: the callback is ignored
+	}{
+		{name: "code", query: "code=abc&state=s1", wantCode: "abc"},
+		{name: "wrong state", query: "code=abc&state=s2", wantErr: true, wantState: true},
+		{name: "missing state", query: "code=abc", wantErr: true, wantState: true},
+		{name: "forged error", query: "error=access_denied&state=s2", wantErr: true, wantState: true},
+		{name: "denied", query: "error=access_denied&state=s1", wantErr: true},
+		{name: "no code", query: "state=s1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := readCallback(query, "s1")
+			if result.code != tt.wantCode {
+				t.Errorf("code = %q, want %q", result.code, tt.wantCode)
+			}
+			if (result.err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", result.err, tt.wantErr)
+			}
+			if errors.Is(result.err, errStateMismatch) != tt.wantState {
+				t.Errorf("err = %v, want state mismatch %v", result.err, tt.wantState)
+			}
+		})
+	}
+}
+
+// freeRedirectURL returns a localhost callback URL on a free port
+func freeRedirectURL(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+	return "http://" + addr + "/callback"
+}
+
+func TestLoginIgnoresForeignCallbacks(t *testing.T) {
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "real" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"at","refresh_token":"rt","expires_in":3600}`)
+	}))
+	t.Cleanup(tokens.Close)
+
+	config := &Config{
+		ClientID:     "client",
+		AuthorizeURL: "https://auth.invalid/authorize",
+		TokenURL:     tokens.URL,
+		RedirectURL:  freeRedirectURL(t),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses := make(chan int, 2)
+	open := func(authURL string) {
+		parsed, err := url.Parse(authURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		state := parsed.Query().Get("state")
+		go func() {
+			// A stale tab or a forged request first, then the real redirect
+			for _, query := range []string{"error=access_denied&state=forged", "code=real&state=" + url.QueryEscape(state)} {
+				resp, err := http.Get(config.RedirectURL + "?" + query)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				_ = resp.Body.Close()
+				statuses <- resp.StatusCode
+			}
+		}()
+	}
+
+	token, err := config.Login(ctx, open)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if token.AccessToken != "at" || token.RefreshToken != "rt" {
+		t.Errorf("token = %+v", token)
+	}
+	if got := <-statuses; got != http.StatusBadRequest {
+		t.Errorf("foreign callback status = %d, want %d", got, http.StatusBadRequest)
+	}
+}
+
+func TestLoginTimesOutWithoutCallback(t *testing.T) {
+	config := &Config{ClientID: "client", AuthorizeURL: "https://auth.invalid/authorize", RedirectURL: freeRedirectURL(t)}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	open := func(string) {
+		resp, err := http.Get(config.RedirectURL + "?code=x&state=forged")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}
+	if _, err := config.Login(ctx, open); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Login() error = %v, want a timeout", err)
+	}
+}
@@ -0,0 +1,227 @@
+// Package oauth implements the OAuth 2.0 authorization-code flow with PKCE for
+// command-line clients: a localhost listener receives the authorization code,
+// which is exchanged for access and refresh tokens.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// expiryDelta renews access tokens slightly before they actually expire
+const expiryDelta = time.Minute
+
+// Config describes an OAuth 2.0 client and the endpoints of its provider
+type Config struct {
+	ClientID     string
+	ClientSecret string // Optional: public clients only rely on PKCE
+	AuthorizeURL string
+	TokenURL     string
+	RevokeURL    string // Optional: tokens are only deleted locally when empty
+	RedirectURL  string // Must be a http://127.0.0.1 or http://localhost URL registered for the client
+	Scopes       []string
+	AuthParams   map[string]string // Extra authorization parameters (e.g. audience)
+	HTTPClient   *http.Client      // nil means a client with a 30s timeout
+}
+
+// Token is the result of a token exchange or refresh
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Valid reports whether the access token can still be used
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// Error is an error response of the token endpoint (RFC 6749 section 5.2)
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	StatusCode  int    `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth error %s: %s", e.Code, e.Description)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("oauth error %s", e.Code)
+	}
+	return fmt.Sprintf("oauth endpoint returned status %d", e.StatusCode)
+}
+
+// IsInvalidGrant reports whether err means the refresh token was revoked or expired
+func IsInvalidGrant(err error) bool {
+	var oauthErr *Error
+	return errors.As(err, &oauthErr) && oauthErr.Code == "invalid_grant"
+}
+
+// NewVerifier returns a random PKCE code verifier (RFC 7636)
+func NewVerifier() (string, error) {
+	return randomString(32)
+}
+
+// Challenge returns the S256 code challenge of a verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString returns n random bytes, base64url-encoded
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// AuthCodeURL returns the URL the user opens to grant access
+func (c *Config) AuthCodeURL(state, challenge string) string {
+	params := url.Values{}
+	for key, value := range c.AuthParams {
+		params.Set(key, value)
+	}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.ClientID)
+	params.Set("redirect_uri", c.RedirectURL)
+	params.Set("state", state)
+	params.Set("code_challenge", challenge)
+	params.Set("code_challenge_method", "S256")
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	sep := "?"
+	if strings.Contains(c.AuthorizeURL, "?") {
+		sep = "&"
+	}
+	return c.AuthorizeURL + sep + params.Encode()
+}
+
+// Exchange trades an authorization code for tokens
+func (c *Config) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.RedirectURL},
+		"code_verifier": {verifier},
+	})
+}
+
+// Refresh returns a new access token; the refresh token is kept when the provider does not rotate it
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	token, err := c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// Revoke invalidates a token (RFC 7009); hint is "access_token" or "refresh_token"
+func (c *Config) Revoke(ctx context.Context, token, hint string) error {
+	if c.RevokeURL == "" {
+		return nil
+	}
+
+	resp, err := c.post(ctx, c.RevokeURL, url.Values{
+		"token":           {token},
+		"token_type_hint": {hint},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	return nil
+}
+
+// requestToken calls the token endpoint and decodes the returned token
+func (c *Config) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	resp, err := c.post(ctx, c.TokenURL, form)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	var body struct {
+		Token
+		ExpiresIn int64 `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	token := body.Token
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// post sends a form with the client credentials
+func (c *Config) post(ctx context.Context, endpoint string, form url.Values) (*http.Response, error) {
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s: %w", endpoint, err)
+	}
+	return resp, nil
+}
+
+// decodeError reads an OAuth error response
+func decodeError(resp *http.Response) error {
+	oauthErr := &Error{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = json.Unmarshal(body, oauthErr)
+	return oauthErr
+}
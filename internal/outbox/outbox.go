@@ -0,0 +1,303 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/cache"
+	"github.com/hyphaene/hexa/internal/config"
+	"github.com/hyphaene/hexa/internal/jira"
+)
+
+// OutboxDirName is the outbox directory name under the state directory of the profile (config.StateDir)
+const OutboxDirName = "outbox"
+
+// Operation types
+const (
+	OpComment    = "comment"
+	OpTransition = "transition"
+	OpAssign     = "assign"
+	OpLabel      = "label"
+)
+
+var (
+	// ErrConflict is returned on replay when the ticket changed since the operation was queued
+	ErrConflict = errors.New("ticket changed since the operation was queued")
+	// ErrSkipped is returned on replay when the ticket already has the target status:
+	// the operation is removed from the outbox without being sent
+	ErrSkipped = errors.New("already applied in the meantime")
+	// ErrUncertain is returned by Submit when Jira stopped answering after the request was sent:
+	// the operation may have been applied, so it is not queued (a replay could apply it twice).
+	// Replay returns it for such operations, flagged Uncertain and only sent again with force.
+	ErrUncertain = errors.New("jira did not answer, the operation may have been applied")
+)
+
+// Operation represents a Jira write operation, persisted when Jira is unreachable
+type Operation struct {
+	ID         string    `json:"id"`                   // Unique ID, also the file name
+	Type       string    `json:"type"`                 // comment, transition, assign or label
+	IssueKey   string    `json:"issueKey"`             // e.g., "PROJ-123"
+	Body       string    `json:"body,omitempty"`       // Comment text
+	Status     string    `json:"status,omitempty"`     // Target status of a transition
+	Assignee   string    `json:"assignee,omitempty"`   // Username of the new assignee
+	Labels     []string  `json:"labels,omitempty"`     // Labels to add
+	BaseStatus string    `json:"baseStatus,omitempty"` // Ticket status known when the operation was requested (cached)
+	QueuedAt   time.Time `json:"queuedAt"`             // Timestamp when the operation was queued
+	LastError  string    `json:"lastError,omitempty"`  // Error of the last replay attempt
+	Uncertain  bool      `json:"uncertain,omitempty"`  // A replay got no answer: Jira may have applied it
+}
+
+// Describe returns a one-line human-readable summary of the operation
+func (op *Operation) Describe() string {
+	switch op.Type {
+	case OpComment:
+		return fmt.Sprintf("%s: comment %q", op.IssueKey, op.Body)
+	case OpTransition:
+		return fmt.Sprintf("%s: move to '%s'", op.IssueKey, op.Status)
+	case OpAssign:
+		return fmt.Sprintf("%s: assign to %s", op.IssueKey, op.Assignee)
+	case OpLabel:
+		return fmt.Sprintf("%s: add labels %s", op.IssueKey, strings.Join(op.Labels, ", "))
+	default:
+		return fmt.Sprintf("%s: unknown operation '%s'", op.IssueKey, op.Type)
+	}
+}
+
+// Apply sends the operation to Jira API
+func Apply(ctx context.Context, op *Operation) error {
+	var err error
+	switch op.Type {
+	case OpComment:
+		err = jira.AddComment(ctx, op.IssueKey, op.Body)
+	case OpTransition:
+		err = transition(ctx, op.IssueKey, op.Status)
+	case OpAssign:
+		err = jira.AssignIssue(ctx, op.IssueKey, op.Assignee)
+	case OpLabel:
+		err = jira.AddLabels(ctx, op.IssueKey, op.Labels)
+	default:
+		return fmt.Errorf("unknown operation type '%s'", op.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	cache.InvalidateIssue(op.IssueKey)
+	return nil
+}
+
+// transition moves a ticket using the cached transitions, refreshed once if the target is missing
+func transition(ctx context.Context, issueKey string, status string) error {
+	transitions, err := cache.Transitions(ctx, issueKey)
+	if err != nil {
+		return fmt.Errorf("fetching transitions: %w", err)
+	}
+
+	target, err := jira.FindTransition(transitions, status)
+	if err != nil && !jira.IsOffline() {
+		// Cached transitions may predate a status change: retry with fresh ones
+		cache.InvalidateIssue(issueKey)
+		if transitions, err = cache.Transitions(ctx, issueKey); err != nil {
+			return fmt.Errorf("fetching transitions: %w", err)
+		}
+		target, err = jira.FindTransition(transitions, status)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", issueKey, err)
+	}
+
+	return jira.ApplyTransition(ctx, issueKey, target.ID)
+}
+
+// Submit sends the operation to Jira, or persists it in the outbox when it could not reach Jira.
+// It reports whether the operation was queued instead of sent. When Jira may have received it
+// (e.g. a timeout waiting for the response), ErrUncertain is returned instead.
+func Submit(ctx context.Context, op *Operation) (bool, error) {
+	err := Apply(ctx, op)
+	if err == nil {
+		return false, nil
+	}
+	if !jira.IsConnectionError(err) {
+		return false, err
+	}
+	if !jira.IsUnsentError(err) {
+		return false, fmt.Errorf("%w: %w", ErrUncertain, err)
+	}
+
+	if err := Save(op); err != nil {
+		return false, fmt.Errorf("queuing operation: %w", err)
+	}
+
+	return true, nil
+}
+
+// Replay sends a queued operation and removes it from the outbox on success.
+// Unless force is set, the operation is refused when the ticket status changed since it was queued,
+// and a transition to the status the ticket already has is dropped with ErrSkipped. Only the
+// status is compared: a comment, assignee or label changed in the meantime is not a conflict.
+//
+// When Jira stops answering after receiving the operation, it is kept but flagged Uncertain and
+// ErrUncertain is returned: it is not sent again without force, except a transition, which the
+// status check above skips once applied.
+func Replay(ctx context.Context, op *Operation, force bool) error {
+	if op.Uncertain && !force && op.Type != OpTransition {
+		return fmt.Errorf("%w: check %s before replaying it", ErrUncertain, op.IssueKey)
+	}
+	if !force && (op.BaseStatus != "" || op.Type == OpTransition) {
+		ticket, err := jira.FetchIssue(ctx, op.IssueKey)
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", op.IssueKey, err)
+		}
+
+		current := ticket.Fields.Status.Name
+		if op.Type == OpTransition && strings.EqualFold(current, op.Status) {
+			if err := Drop(op.ID); err != nil {
+				return err
+			}
+			return ErrSkipped
+		}
+		if op.BaseStatus != "" && !strings.EqualFold(current, op.BaseStatus) && changedSince(ticket, op.QueuedAt) {
+			return fmt.Errorf("%w: status was '%s', now '%s'", ErrConflict, op.BaseStatus, current)
+		}
+	}
+
+	if err := Apply(ctx, op); err != nil {
+		// A timeout or an interruption after sending leaves the operation possibly applied
+		uncertain := (jira.IsConnectionError(err) || ctx.Err() != nil) && !jira.IsUnsentError(err)
+		if ctx.Err() != nil && !uncertain {
+			return err // Interrupted before sending: keep the operation as it was
+		}
+		op.LastError = err.Error()
+		if uncertain {
+			op.Uncertain = true
+			err = fmt.Errorf("%w: %w", ErrUncertain, err)
+		}
+		if saveErr := write(op); saveErr != nil {
+			return fmt.Errorf("%w (saving outbox: %v)", err, saveErr)
+		}
+		return err
+	}
+
+	return Drop(op.ID)
+}
+
+// changedSince reports whether the ticket was updated after t (true when Jira does not say).
+// BaseStatus comes from the sprint cache, which may predate changes made before the operation
+// was queued: those are not conflicts.
+func changedSince(ticket *jira.Ticket, t time.Time) bool {
+	updated, ok := ticket.Fields.UpdatedAt()
+	return !ok || updated.After(t)
+}
+
+// Save persists a new operation in the outbox
+func Save(op *Operation) error {
+	if op.QueuedAt.IsZero() {
+		op.QueuedAt = time.Now()
+	}
+	if op.ID == "" {
+		op.ID = fmt.Sprintf("%s-%s-%s", op.QueuedAt.UTC().Format("20060102T150405.000"), op.Type, op.IssueKey)
+	}
+
+	return write(op)
+}
+
+// List returns the queued operations, oldest first
+func List() ([]Operation, error) {
+	dir, err := getOutboxDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox: %w", err)
+	}
+
+	ops := make([]Operation, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outbox entry: %w", err)
+		}
+		var op Operation
+		if err := json.Unmarshal(data, &op); err != nil {
+			return nil, fmt.Errorf("corrupted outbox entry %s: %w", filepath.Base(path), err)
+		}
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].QueuedAt.Before(ops[j].QueuedAt)
+	})
+	return ops, nil
+}
+
+// Drop removes an operation from the outbox
+func Drop(id string) error {
+	path, err := getOperationPath(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no queued operation with id '%s'", id)
+		}
+		return fmt.Errorf("failed to remove outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// write stores the operation file
+func write(op *Operation) error {
+	path, err := getOperationPath(op.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// getOutboxDir returns the outbox directory
+func getOutboxDir() (string, error) {
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, OutboxDirName), nil
+}
+
+// getOperationPath returns the file path of an operation
+func getOperationPath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid operation id '%s'", id)
+	}
+
+	dir, err := getOutboxDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, id+".json"), nil
+}
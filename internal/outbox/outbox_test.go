@@ -0,0 +1,240 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyphaene/hexa/internal/jira"
+	"github.com/hyphaene/hexa/internal/jira/jiratest"
+	"github.com/spf13/viper"
+)
+
+// setup starts a fake Jira with one "To Do" ticket, PROJ-1, and isolates the outbox in a temporary home
+func setup(t *testing.T) *jiratest.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	jira.SetOffline(false)
+	t.Cleanup(func() { jira.SetOffline(false) })
+
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+	server.Configure()
+	server.AddIssue(1, jira.Ticket{Key: "PROJ-1", Fields: jira.Fields{Summary: "Login", Status: jira.Status{Name: "To Do"}}})
+	return server
+}
+
+func TestSubmit(t *testing.T) {
+	t.Run("sent", func(t *testing.T) {
+		server := setup(t)
+
+		queued, err := Submit(context.Background(), &Operation{Type: OpComment, IssueKey: "PROJ-1", Body: "done"})
+		if err != nil || queued {
+			t.Fatalf("Submit() = %v, %v, want sent", queued, err)
+		}
+		if comments := server.Comments("PROJ-1"); len(comments) != 1 || comments[0] != "done" {
+			t.Errorf("comments = %v, want [done]", comments)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := setup(t)
+		server.Close() // Connection refused: nothing was sent
+
+		queued, err := Submit(context.Background(), &Operation{Type: OpComment, IssueKey: "PROJ-1", Body: "done"})
+		if err != nil || !queued {
+			t.Fatalf("Submit() = %v, %v, want queued", queued, err)
+		}
+		if ops, _ := List(); len(ops) != 1 {
+			t.Errorf("outbox has %d operation(s), want 1", len(ops))
+		}
+	})
+
+	t.Run("no answer", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		jira.SetOffline(false)
+		t.Cleanup(func() { jira.SetOffline(false) })
+		jira.SetRequestTimeout(50 * time.Millisecond)
+		t.Cleanup(func() { jira.SetRequestTimeout(0) })
+
+		// Jira receives the comment but does not answer before the request timeout
+		release := make(chan struct{})
+		hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(hanging.Close)
+		t.Cleanup(func() { close(release) })
+		viper.Set("jira.url", hanging.URL)
+		viper.Set("jira.token", "test-token")
+
+		queued, err := Submit(context.Background(), &Operation{Type: OpComment, IssueKey: "PROJ-1", Body: "done"})
+		if !errors.Is(err, ErrUncertain) || queued {
+			t.Fatalf("Submit() = %v, %v, want ErrUncertain", queued, err)
+		}
+		if ops, _ := List(); len(ops) != 0 {
+			t.Errorf("outbox has %d operation(s), want none", len(ops))
+		}
+	})
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name      string
+		op        Operation
+		queuedAgo time.Duration // Age of the operation, the ticket was created just before the test
+		update    func(*jira.Ticket)
+		force     bool
+		wantErr   error
+		wantKept  bool
+		wantState string
+	}{
+		{
+			name:      "unchanged ticket",
+			op:        Operation{Type: OpTransition, IssueKey: "PROJ-1", Status: "In Progress", BaseStatus: "To Do"},
+			queuedAgo: time.Minute,
+			wantState: "In Progress",
+		},
+		{
+			name:      "status changed after queuing",
+			op:        Operation{Type: OpTransition, IssueKey: "PROJ-1", Status: "In Progress", BaseStatus: "To Do"},
+			queuedAgo: time.Minute,
+			update:    func(ticket *jira.Ticket) { ticket.Fields.Status.Name = "Blocked" },
+			wantErr:   ErrConflict,
+			wantKept:  true,
+			wantState: "Blocked",
+		},
+		{
+			name:      "conflict forced",
+			op:        Operation{Type: OpTransition, IssueKey: "PROJ-1", Status: "In Progress", BaseStatus: "To Do"},
+			queuedAgo: time.Minute,
+			update:    func(ticket *jira.Ticket) { ticket.Fields.Status.Name = "Blocked" },
+			force:     true,
+			wantState: "In Progress",
+		},
+		{
+			name:      "stale cached base status",
+			op:        Operation{Type: OpTransition, IssueKey: "PROJ-1", Status: "In Progress", BaseStatus: "New"},
+			queuedAgo: -time.Minute, // Queued after the last change of the ticket
+			wantState: "In Progress",
+		},
+		{
+			name:      "already moved",
+			op:        Operation{Type: OpTransition, IssueKey: "PROJ-1", Status: "in progress", BaseStatus: "To Do"},
+			queuedAgo: time.Minute,
+			update:    func(ticket *jira.Ticket) { ticket.Fields.Status.Name = "In Progress" },
+			wantErr:   ErrSkipped,
+			wantState: "In Progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setup(t)
+			op := tt.op
+			op.QueuedAt = time.Now().Add(-tt.queuedAgo)
+			if err := Save(&op); err != nil {
+				t.Fatal(err)
+			}
+			if tt.update != nil {
+				server.UpdateIssue("PROJ-1", tt.update)
+			}
+
+			err := Replay(context.Background(), &op, tt.force)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Replay() error = %v, want %v", err, tt.wantErr)
+			}
+			ops, err := List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kept := len(ops) == 1; kept != tt.wantKept {
+				t.Errorf("operation kept = %v, want %v", kept, tt.wantKept)
+			}
+			if ticket, _ := server.Issue("PROJ-1"); ticket.Fields.Status.Name != tt.wantState {
+				t.Errorf("status = %q, want %q", ticket.Fields.Status.Name, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestReplayWithoutAnswer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	jira.SetOffline(false)
+	t.Cleanup(func() { jira.SetOffline(false) })
+	jira.SetRequestTimeout(50 * time.Millisecond)
+	t.Cleanup(func() { jira.SetRequestTimeout(0) })
+
+	// Jira receives each request but never answers before the request timeout
+	var received atomic.Int32
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		<-release
+	}))
+	t.Cleanup(hanging.Close)
+	t.Cleanup(func() { close(release) })
+	viper.Set("jira.url", hanging.URL)
+	viper.Set("jira.token", "test-token")
+
+	op := &Operation{Type: OpComment, IssueKey: "PROJ-1", Body: "done"}
+	if err := Save(op); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replay(context.Background(), op, false); !errors.Is(err, ErrUncertain) {
+		t.Fatalf("Replay() error = %v, want ErrUncertain", err)
+	}
+
+	ops, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || !ops[0].Uncertain {
+		t.Fatalf("outbox = %+v, want the operation kept as uncertain", ops)
+	}
+
+	// The comment may have been posted: a second replay does not send it again
+	if err := Replay(context.Background(), &ops[0], false); !errors.Is(err, ErrUncertain) {
+		t.Errorf("second Replay() error = %v, want ErrUncertain", err)
+	}
+	if got := received.Load(); got != 1 {
+		t.Errorf("Jira received %d request(s), want 1", got)
+	}
+}
+
+func TestSaveListDrop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Now()
+	newer := &Operation{Type: OpComment, IssueKey: "PROJ-2", Body: "b", QueuedAt: now}
+	older := &Operation{Type: OpLabel, IssueKey: "PROJ-1", Labels: []string{"x"}, QueuedAt: now.Add(-time.Hour)}
+	for _, op := range []*Operation{newer, older} {
+		if err := Save(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ops, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0].ID != older.ID || ops[1].ID != newer.ID {
+		t.Fatalf("List() = %v, want oldest first", ops)
+	}
+
+	if err := Drop(older.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := Drop(older.ID); err == nil {
+		t.Error("Drop() of a dropped operation succeeded")
+	}
+	if err := Drop("../escape"); err == nil {
+		t.Error("Drop() accepted a path")
+	}
+	if ops, _ := List(); len(ops) != 1 {
+		t.Errorf("List() after Drop = %d operation(s), want 1", len(ops))
+	}
+}
@@ -5,13 +5,19 @@ import (
 	"os"
 
 	"github.com/hyphaene/hexa/cmd"
+	"github.com/hyphaene/hexa/cmd/alias"
 	"github.com/hyphaene/hexa/internal/config"
-	_ "github.com/hyphaene/hexa/internal/env"
+	"github.com/hyphaene/hexa/internal/env"
+	"github.com/hyphaene/hexa/internal/logger"
 
 	// Import commands to trigger their init() functions
+	_ "github.com/hyphaene/hexa/cmd/auth"
+	_ "github.com/hyphaene/hexa/cmd/cache"
 	_ "github.com/hyphaene/hexa/cmd/config"
 	_ "github.com/hyphaene/hexa/cmd/jira"
+	_ "github.com/hyphaene/hexa/cmd/jira/outbox"
 	_ "github.com/hyphaene/hexa/cmd/jira/ticket"
+	_ "github.com/hyphaene/hexa/cmd/profile"
 	_ "github.com/hyphaene/hexa/cmd/self"
 )
 
@@ -23,8 +29,16 @@ var (
 )
 
 func main() {
-	// Initialize configuration before anything else
-	config.Initialize()
+	// DEBUG=true shows debug logs from the very start, configuration loading included
+	if env.Debug {
+		_ = logger.Configure(logger.Options{Verbose: true})
+	}
+
+	// Initialize configuration before anything else (--config and --profile are needed before cobra parses flags)
+	config.SetConfigFile(config.ConfigFlag(os.Args[1:]))
+	config.SetProfile(config.ProfileFlag(os.Args[1:]))
+	cmd.SetConfigError(config.Initialize())
+	alias.Register() // Aliases come from the configuration, loaded just above
 	cmd.SetVersionInfo(version, commit, date)
 
 	if err := cmd.Execute(); err != nil {